@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// tempMarker is part of every temporary file name written by
+// writeFileAtomic. Such files never end in .md, so loaders skip them.
+const tempMarker = ".tmp-"
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory that is fsynced and then renamed over path. Readers, and a
+// crash at any point, see either the old content or the new, never a
+// truncated file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+tempMarker+"*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// renameDurable renames oldPath to newPath and fsyncs both directories so
+// the move survives a crash.
+func renameDurable(oldPath, newPath string) error {
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(newPath))
+	if filepath.Dir(oldPath) != filepath.Dir(newPath) {
+		syncDir(filepath.Dir(oldPath))
+	}
+	return nil
+}
+
+// syncDir flushes a directory entry update to disk. It is best effort:
+// some platforms and file systems do not support syncing directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
+
+// rewriteAndMove atomically rewrites t at its current path and then renames
+// it to newPath. After each step exactly one complete copy of the task is on
+// disk, so an interrupted archive or restore never loses or duplicates it.
+// The change is journaled under summary. The caller holds the lock.
+func (fs *FileStore) rewriteAndMove(t *TaskWithPath, newPath, summary string) error {
+	if _, err := os.Stat(t.FilePath); err != nil {
+		return fmt.Errorf("failed to read task file: %w", err)
+	}
+	if err := checkUnchanged(t); err != nil {
+		return err
+	}
+	if err := fs.assignID(t.Task); err != nil {
+		return err
+	}
+	before := snapshot(t.FilePath)
+	touch(t.Task)
+	content := []byte(fs.taskToMarkdown(t.Task))
+	if err := writeFileAtomic(t.FilePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write task file: %w", err)
+	}
+	t.Hash = contentHash(content)
+	if err := renameDurable(t.FilePath, newPath); err != nil {
+		return fmt.Errorf("failed to move task file: %w", err)
+	}
+	fs.record(summary, fs.change(t.FilePath, before, nil), fs.change(newPath, nil, content))
+	t.FilePath = newPath
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "task.md")
+
+	if err := writeFileAtomic(path, []byte("first"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, []byte("second"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "second" {
+		t.Errorf("Expected the file to be replaced, got %q", data)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %d entries", len(entries))
+	}
+
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "task.md"), []byte("x"), 0644); err == nil {
+		t.Error("Expected an error writing into a missing directory")
+	}
+}
+
+func TestFileStore_ArchiveIsAllOrNothing(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{Title: "Ship", Status: StatusTodo})
+	tasks, _ := store.LoadAllTasks()
+	task := tasks["work"][0]
+
+	// A stray temporary file from an interrupted write is never loaded
+	stray := filepath.Join(filepath.Dir(task.FilePath), ".ship.md"+tempMarker+"123")
+	os.WriteFile(stray, []byte("---\ntitle: half"), 0644)
+
+	// Archiving a task whose file has vanished must not invent an archive copy
+	gone := &TaskWithPath{Task: &Task{Title: "Gone"}, Topic: "work", FilePath: filepath.Join(filepath.Dir(task.FilePath), "gone.md")}
+	if err := store.ArchiveTask(gone); err == nil {
+		t.Error("Expected archiving a missing file to fail")
+	}
+
+	if err := store.ArchiveTask(task); err != nil {
+		t.Fatal(err)
+	}
+	active, _ := store.LoadAllTasks()
+	archived, _ := store.LoadArchivedTasks()
+	if len(active["work"]) != 0 || len(archived["work"]) != 1 {
+		t.Fatalf("Expected exactly one archived copy, got %d active and %d archived", len(active["work"]), len(archived["work"]))
+	}
+	if archived["work"][0].Task.Status != StatusDone {
+		t.Errorf("Expected the archived copy to be done, got %s", archived["work"][0].Task.Status)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// checklistPattern matches a Markdown task-list line such as "  - [x] text".
+var checklistPattern = regexp.MustCompile(`^(\s*)([-*+]) \[([ xX])\] (.*)$`)
+
+// checklistItem is one "- [ ]" line in a task body. Indented items are
+// nested under the item above them.
+type checklistItem struct {
+	line  int // index into the body's lines
+	depth int // leading whitespace width
+	text  string
+	done  bool
+}
+
+// parseChecklist returns the checklist items in body, in order.
+func parseChecklist(body string) []checklistItem {
+	var items []checklistItem
+	for i, line := range strings.Split(body, "\n") {
+		m := checklistPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		items = append(items, checklistItem{
+			line:  i,
+			depth: len(strings.ReplaceAll(m[1], "\t", "  ")),
+			text:  m[4],
+			done:  m[3] != " ",
+		})
+	}
+	return items
+}
+
+// progress returns the checklist progress as "done/total", or "" when the
+// task has no checklist.
+func (t *Task) progress() string {
+	items := parseChecklist(t.Body)
+	if len(items) == 0 {
+		return ""
+	}
+	done := 0
+	for _, item := range items {
+		if item.done {
+			done++
+		}
+	}
+	return fmt.Sprintf("%d/%d", done, len(items))
+}
+
+// materializeBody makes a generated body explicit so it can be edited.
+func (t *Task) materializeBody() {
+	if t.Body == "" {
+		t.Body = defaultTaskBody(t)
+	}
+}
+
+// addChecklistItem appends an unchecked item after the last checklist item,
+// or at the end of the body when there is none yet.
+func (t *Task) addChecklistItem(text string) {
+	t.materializeBody()
+	lines := strings.Split(t.Body, "\n")
+	entry := "- [ ] " + text
+	if items := parseChecklist(t.Body); len(items) > 0 {
+		last := items[len(items)-1]
+		entry = strings.Repeat(" ", last.depth) + entry
+		lines = append(lines[:last.line+1], append([]string{entry}, lines[last.line+1:]...)...)
+		t.Body = strings.Join(lines, "\n")
+		return
+	}
+	body := strings.TrimRight(t.Body, "\n")
+	t.Body = body + "\n\n" + entry + "\n"
+}
+
+// setChecklistItem checks or unchecks the item at index (0-based).
+func (t *Task) setChecklistItem(index int, done bool) error {
+	items := parseChecklist(t.Body)
+	if index < 0 || index >= len(items) {
+		return fmt.Errorf("no checklist item %d (task has %d)", index+1, len(items))
+	}
+	lines := strings.Split(t.Body, "\n")
+	mark := "[ ]"
+	if done {
+		mark = "[x]"
+	}
+	m := checklistPattern.FindStringSubmatch(lines[items[index].line])
+	lines[items[index].line] = m[1] + m[2] + " " + mark + " " + m[4]
+	t.Body = strings.Join(lines, "\n")
+	return nil
+}
+
+// findChecklistItem resolves ref, either a 1-based number or a unique
+// case-insensitive substring of the item text, to a 0-based index.
+func findChecklistItem(items []checklistItem, ref string) (int, error) {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(items) {
+			return 0, fmt.Errorf("no checklist item %d (task has %d)", n, len(items))
+		}
+		return n - 1, nil
+	}
+	match := -1
+	q := strings.ToLower(ref)
+	for i, item := range items {
+		if strings.Contains(strings.ToLower(item.text), q) {
+			if match >= 0 {
+				return 0, fmt.Errorf("%q matches more than one checklist item", ref)
+			}
+			match = i
+		}
+	}
+	if match < 0 {
+		return 0, fmt.Errorf("no checklist item matches %q", ref)
+	}
+	return match, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestChecklistEditing(t *testing.T) {
+	task := &Task{Title: "Release", Body: "\n# Release\n\n- [x] tag\n  - [ ] changelog\n- [ ] announce\n\nNotes stay here.\n"}
+
+	if got := task.progress(); got != "1/3" {
+		t.Errorf("Expected progress 1/3, got %q", got)
+	}
+	if items := parseChecklist(task.Body); items[1].depth != 2 || items[1].text != "changelog" {
+		t.Errorf("Expected a nested changelog item, got %+v", items[1])
+	}
+
+	if err := task.setChecklistItem(1, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := task.setChecklistItem(0, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := task.setChecklistItem(3, true); err == nil {
+		t.Error("Expected an out-of-range item to fail")
+	}
+	task.addChecklistItem("blog post")
+
+	want := "\n# Release\n\n- [ ] tag\n  - [x] changelog\n- [ ] announce\n- [ ] blog post\n\nNotes stay here.\n"
+	if task.Body != want {
+		t.Errorf("Unexpected body:\n%q\nwant:\n%q", task.Body, want)
+	}
+
+	items := parseChecklist(task.Body)
+	if i, err := findChecklistItem(items, "blog"); err != nil || i != 3 {
+		t.Errorf("Expected blog to resolve to index 3, got %d, %v", i, err)
+	}
+	if _, err := findChecklistItem(items, "an"); err == nil {
+		t.Error("Expected an ambiguous match to fail")
+	}
+}
+
+func TestSubCommands(t *testing.T) {
+	store := NewMemoryStorage()
+	runWithStore(NewAddCmd(store), "ops/On-call handoff", "-d", "weekly")
+
+	if out := runWithStore(NewSubCmd(store), "add", "ops/On-call handoff", "update", "runbook"); !strings.Contains(out, "Subtask added: update runbook (0/1)") {
+		t.Fatalf("Expected sub add to succeed, got: %s", out)
+	}
+	runWithStore(NewSubCmd(store), "add", "ops/On-call handoff", "page test")
+	if out := runWithStore(NewSubCmd(store), "check", "ops/On-call handoff", "2"); !strings.Contains(out, "Checked: page test (1/2)") {
+		t.Errorf("Expected sub check to succeed, got: %s", out)
+	}
+	if out := runWithStore(NewSubCmd(store), "uncheck", "ops/On-call handoff", "page"); !strings.Contains(out, "(0/2)") {
+		t.Errorf("Expected sub uncheck to succeed, got: %s", out)
+	}
+
+	body := store.tasks[0].Task.Body
+	if !strings.Contains(body, "# On-call handoff") || !strings.Contains(body, "- [ ] update runbook\n- [ ] page test\n") {
+		t.Errorf("Expected the generated body to be kept with the checklist appended, got %q", body)
+	}
+	if out := runWithStore(NewListCmd(store, nil)); !strings.Contains(out, "0/2") {
+		t.Errorf("Expected list to show progress, got: %s", out)
+	}
+}
+
+func TestTUIChecklistToggle(t *testing.T) {
+	store := NewMemoryStorage()
+	task := &Task{Title: "Chores", Status: StatusTodo, Priority: 3, Body: "- [ ] dishes\n- [ ] laundry\n"}
+	store.SaveTask("", task)
+	tasks, _ := store.LoadAllTasks()
+
+	m := model{tasks: tasks, store: store, expanded: map[string]bool{}}
+	m.buildItems()
+	if len(m.items) != 1 || !strings.Contains(m.items[0].text, "[0/2]") {
+		t.Fatalf("Expected one task row with progress, got %+v", m.items)
+	}
+
+	m2, _ := m.updateListView(tea.KeyMsg{Type: tea.KeySpace})
+	m = m2.(model)
+	if len(m.items) != 3 || !m.items[2].isCheck {
+		t.Fatalf("Expected space to expand the checklist, got %+v", m.items)
+	}
+
+	m.selected = 2
+	m2, _ = m.updateListView(tea.KeyMsg{Type: tea.KeySpace})
+	m = m2.(model)
+	if task.progress() != "1/2" || !strings.Contains(task.Body, "- [x] laundry") {
+		t.Errorf("Expected laundry to be checked, got %q", task.Body)
+	}
+	if m.mode != listView {
+		t.Errorf("Expected to stay in the list view")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// runWithStore executes cmd with args and returns its combined output.
+func runWithStore(cmd *cobra.Command, args ...string) string {
+	var out strings.Builder
+	cmd.SetArgs(args)
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.Execute()
+	return out.String()
+}
+
+// Commands depend only on Storage, so they run against the in-memory store.
+func TestCommandsWithMemoryStorage(t *testing.T) {
+	store := NewMemoryStorage()
+
+	if out := runWithStore(NewAddCmd(store), "work/Memory task", "-d", "in memory"); !strings.Contains(out, "Task added") {
+		t.Fatalf("Expected add to succeed, got: %s", out)
+	}
+	if out := runWithStore(NewEditCmd(store), "work/Memory task", "--priority", "1"); !strings.Contains(out, "Task updated") {
+		t.Fatalf("Expected edit to succeed, got: %s", out)
+	}
+	if out := runWithStore(NewMoveCmd(store), "work/Memory task", "home"); !strings.Contains(out, "Task moved to topic: home") {
+		t.Fatalf("Expected move to succeed, got: %s", out)
+	}
+	if out := runWithStore(NewCopyCmd(store), "home/Memory task", "later"); !strings.Contains(out, "Task copied to topic: later") {
+		t.Fatalf("Expected copy to succeed, got: %s", out)
+	}
+	if out := runWithStore(NewShowCmd(store), "home/Memory task"); !strings.Contains(out, "in memory") {
+		t.Fatalf("Expected show to print the description, got: %s", out)
+	}
+	if out := runWithStore(NewCompleteCmd(store), "home/Memory task"); !strings.Contains(out, "Task completed and archived") {
+		t.Fatalf("Expected complete to succeed, got: %s", out)
+	}
+	if out := runWithStore(NewDeleteCmd(store), "later/Memory task"); !strings.Contains(out, "Task deleted") {
+		t.Fatalf("Expected delete to succeed, got: %s", out)
+	}
+
+	tasks, _ := store.LoadAllTasks()
+	for topic, list := range tasks {
+		if len(list) > 0 {
+			t.Errorf("Expected no active tasks, found %d in %q", len(list), topic)
+		}
+	}
+	if len(store.archived) != 1 || store.archived[0].Task.Priority != 1 {
+		t.Errorf("Expected the edited task in the archive, got %+v", store.archived)
+	}
+}
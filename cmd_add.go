@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -11,19 +12,57 @@ import (
 
 // Use CLI color palette from cmd_list.go
 
-func NewAddCmd(store *FileStore) *cobra.Command {
+func NewAddCmd(store Storage) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "add [topic/]title",
 		Short: "Add a new task",
-		Long:  "Add a new task with optional topic path and description, priority, tags, and status.",
+		Long:  "Add a new task with optional topic path and description, priority, tags, status, and due/scheduled dates.",
 		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			title := strings.Join(args, " ")
 
 			description, _ := cmd.Flags().GetString("description")
 			priority, _ := cmd.Flags().GetInt("priority")
 			tags, _ := cmd.Flags().GetStringSlice("tags")
 			status, _ := cmd.Flags().GetString("status")
+			dueFlag, _ := cmd.Flags().GetString("due")
+			scheduledFlag, _ := cmd.Flags().GetString("scheduled")
+			recur, _ := cmd.Flags().GetString("recur")
+			dependsOn, _ := cmd.Flags().GetStringSlice("depends-on")
+			fieldFlags, _ := cmd.Flags().GetStringArray("field")
+
+			now := time.Now()
+			due, _, err := parseDateFlag(dueFlag, now)
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --due: %v", err))
+			}
+			scheduled, _, err := parseDateFlag(scheduledFlag, now)
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --scheduled: %v", err))
+			}
+			if recur == "none" {
+				recur = ""
+			} else if recur != "" {
+				if _, err := parseRecurrence(recur); err != nil {
+					return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --recur: %v", err))
+				}
+			}
+
+			fields, err := parseFieldFlags(fieldFlags, now)
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --field: %v", err))
+			}
+
+			var deps []string
+			if len(dependsOn) > 0 {
+				tasks, err := store.LoadAllTasks()
+				if err == nil {
+					deps, err = resolveDependencies(tasks, dependsOn)
+				}
+				if err != nil {
+					return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --depends-on: %v", err))
+				}
+			}
 
 			// Parse topic from title if contains "/"
 			var topic, taskTitle string
@@ -39,7 +78,7 @@ func NewAddCmd(store *FileStore) *cobra.Command {
 				taskTitle = title
 			}
 
-			ts := StatusTodo
+			ts := initialStatus()
 			if status != "" {
 				ts = TaskStatus(status)
 			}
@@ -50,25 +89,50 @@ func NewAddCmd(store *FileStore) *cobra.Command {
 				Priority:    priority,
 				Tags:        tags,
 				Status:      ts,
+				Due:         due,
+				Scheduled:   scheduled,
+				Recur:       recur,
+				DependsOn:   deps,
 			}
+			task.setFieldValues(fields)
 
-			if err := store.SaveTask(topic, task); err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Error adding task: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
+			// A task added with a closed status goes straight to the archive,
+			// as one undoable change
+			err = journaled(store, fmt.Sprintf("add %q", task.Title), func() error {
+				if err := store.SaveTask(topic, task); err != nil || !task.Status.isClosed() {
+					return err
+				}
+				saved, err := store.GetTask(task.ID)
+				if err == nil {
+					_, err = completeTask(store, saved)
+				}
+				return err
+			})
+			if err != nil {
+				return fail(cmd, err, fmt.Sprintf("Error adding task: %v", err))
 			}
 
 			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
-			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Task added: %s", task.Title)))
+			msg := "Task added: %s"
+			if task.Status.isClosed() {
+				msg = "Task added and archived: %s"
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf(msg, task.Title)))
 			if topic != "" {
 				topicStyle := lipgloss.NewStyle().Foreground(cliSecondary)
 				fmt.Fprintln(cmd.OutOrStdout(), topicStyle.Render(fmt.Sprintf("Topic: %s", topic)))
 			}
+			return nil
 		},
 	}
 	cmd.Flags().StringP("description", "d", "", "Task description")
-	cmd.Flags().IntP("priority", "p", 3, "Task priority (0, 1, 2, ...)")
+	cmd.Flags().VarP(newPriorityValue(3), "priority", "p", "Task priority (0, 1, 2, ...)")
 	cmd.Flags().StringSliceP("tags", "t", []string{}, "Task tags")
-	cmd.Flags().String("status", "", "Task status (todo, in-progress, done, cancelled, paused)")
+	cmd.Flags().Var(new(statusValue), "status", "Task status ("+statusNames()+")")
+	cmd.Flags().String("due", "", "Due date (e.g. tomorrow, \"next fri\", in 3 days, 2026-11-01)")
+	cmd.Flags().String("scheduled", "", "Date to start working on the task (same formats as --due)")
+	cmd.Flags().String("recur", "", "Repeat rule (e.g. daily, \"weekly on mon,thu\", \"monthly on 15\", \"every 3 days after completion\", FREQ=WEEKLY;BYDAY=MO)")
+	cmd.Flags().StringSlice("depends-on", []string{}, "Tasks this one depends on, by ID or topic/title")
+	cmd.Flags().StringArray("field", []string{}, "Custom field as key=value (repeatable)")
 	return cmd
 }
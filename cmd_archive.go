@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// NewArchiveCmd browses and restores archived tasks.
+func NewArchiveCmd(store Storage, cfg *Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Browse and restore archived tasks",
+		Long:  "List, show and restore tasks that were completed and archived.",
+	}
+
+	list := newTaskListCmd(cfg, func(*cobra.Command) (map[string][]*TaskWithPath, error) {
+		return store.LoadArchivedTasks()
+	})
+	list.Use = "list"
+	list.Short = "List archived tasks"
+	list.Long = "List archived tasks with the same filtering, searching, sorting and output options as tada list"
+
+	show := newTaskShowCmd(store, store.LoadArchivedTasks)
+	show.Use = "show [topic/]title|id"
+	show.Short = "Show details for an archived task"
+
+	restore := &cobra.Command{
+		Use:   "restore [topic/]title|id",
+		Short: "Restore an archived task",
+		Long:  "Move an archived task back into its topic with its status reset to todo.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := strings.Join(args, " ")
+
+			archived, err := store.LoadArchivedTasks()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading archive: %v", err))
+			}
+			found, err := resolveTask(cmd, store, archived, input)
+			if err != nil {
+				return fail(cmd, err, fmt.Sprintf("Error restoring task: %s", lookupErrorMessage(err)))
+			}
+			if err := store.RestoreTask(found); err != nil {
+				return fail(cmd, err, fmt.Sprintf("Error restoring task: %v", err))
+			}
+
+			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Task restored: %s", found.Task.Title)))
+			if found.Topic != "" {
+				topicStyle := lipgloss.NewStyle().Foreground(cliSecondary)
+				fmt.Fprintln(cmd.OutOrStdout(), topicStyle.Render(fmt.Sprintf("Topic: %s", found.Topic)))
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(list, show, restore)
+	return cmd
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestArchiveCommands(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{Title: "Ship it", Status: StatusTodo, Priority: 3})
+	store.SaveTask("work", &Task{Title: "Still open", Status: StatusTodo, Priority: 3})
+	if err := store.CompleteTask("work", "Ship it"); err != nil {
+		t.Fatal(err)
+	}
+
+	if out := runWithStore(NewListCmd(store, nil)); strings.Contains(out, "Ship it") {
+		t.Errorf("Expected archived task to be hidden from list, got: %s", out)
+	}
+	if out := runWithStore(NewListCmd(store, nil), "--include-archived"); !strings.Contains(out, "Ship it") || !strings.Contains(out, "Still open") {
+		t.Errorf("Expected list --include-archived to show both tasks, got: %s", out)
+	}
+
+	out := runWithStore(NewArchiveCmd(store, nil), "list", "--simple")
+	if !strings.Contains(out, "Ship it") || strings.Contains(out, "Still open") {
+		t.Errorf("Expected archive list to show only the archived task, got: %s", out)
+	}
+	if out := runWithStore(NewArchiveCmd(store, nil), "show", "work/Ship it"); !strings.Contains(out, "Status: done") || !strings.Contains(out, "Completed: ") {
+		t.Errorf("Expected archive show to print the archived task, got: %s", out)
+	}
+
+	archived, _ := store.LoadArchivedTasks()
+	id := archived["work"][0].Task.ID
+	if out := runWithStore(NewArchiveCmd(store, nil), "restore", id); !strings.Contains(out, "Task restored: Ship it") {
+		t.Fatalf("Expected restore to succeed, got: %s", out)
+	}
+
+	tasks, _ := store.LoadAllTasks()
+	restored, err := findTask(tasks, id)
+	if err != nil {
+		t.Fatalf("Expected restored task among active tasks: %v", err)
+	}
+	if restored.Task.Status != StatusTodo || restored.Task.CompletedAt != nil {
+		t.Errorf("Expected status reset, got %s (completed %v)", restored.Task.Status, restored.Task.CompletedAt)
+	}
+	if archived, _ := store.LoadArchivedTasks(); len(archived["work"]) != 0 {
+		t.Errorf("Expected the archive to be empty, got %d tasks", len(archived["work"]))
+	}
+}
+
+func TestTUIArchiveToggle(t *testing.T) {
+	store := NewMemoryStorage()
+	store.SaveTask("", &Task{Title: "Old", Status: StatusTodo, Priority: 3})
+	store.SaveTask("", &Task{Title: "Current", Status: StatusTodo, Priority: 3})
+	store.CompleteTask("", "Old")
+
+	m := model{store: store, expanded: map[string]bool{}}
+	m2, cmd := m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = m2.(model)
+	m2, _ = m.Update(cmd())
+	m = m2.(model)
+	if !m.showArchived || len(m.items) != 1 || !strings.Contains(m.items[0].text, "Old") {
+		t.Fatalf("Expected the archive view to list the archived task, got %+v", m.items)
+	}
+
+	// Editing keys are ignored in the archive view
+	m2, _ = m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if m2.(model).confirmDelete {
+		t.Error("Expected delete to be disabled in the archive view")
+	}
+
+	m2, cmd = m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	m = m2.(model)
+	m2, _ = m.Update(cmd())
+	m = m2.(model)
+	if len(m.items) != 0 || len(store.tasks) != 2 || store.tasks[1].Task.Status != StatusTodo {
+		t.Errorf("Expected the task to be restored, got items %+v and tasks %+v", m.items, store.tasks)
+	}
+}
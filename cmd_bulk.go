@@ -2,20 +2,25 @@ package main
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
 
+// tagIndex is implemented by stores whose index finds the tasks with a tag
+// without checking every task.
+type tagIndex interface {
+	findTagged(tasks map[string][]*TaskWithPath, tag string) ([]*TaskWithPath, bool)
+}
+
 // Bulk operations: delete, complete, move multiple tasks by query or tag
-func NewBulkCmd(store *FileStore) *cobra.Command {
+func NewBulkCmd(store Storage) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "bulk",
 		Short: "Bulk operations on tasks",
-		Long:  "Perform bulk operations (delete, complete, move) on tasks by search, tag, or status.",
+		Long:  "Perform bulk operations (delete, complete, move) on the tasks matching a query, tag, or status.",
 	}
 
 	var (
@@ -30,62 +35,82 @@ func NewBulkCmd(store *FileStore) *cobra.Command {
 	cmd.Flags().BoolVar(&bulkDelete, "delete", false, "Delete matching tasks")
 	cmd.Flags().BoolVar(&bulkComplete, "complete", false, "Complete (and archive) matching tasks")
 	cmd.Flags().StringVar(&bulkMove, "move", "", "Move matching tasks to this topic")
-	cmd.Flags().StringVar(&bulkQuery, "search", "", "Search query (title, description, tags, topic)")
+	cmd.Flags().StringVarP(&bulkQuery, "search", "q", "", "Query selecting the tasks, as in tada list --search")
 	cmd.Flags().StringVar(&bulkTag, "tag", "", "Filter by tag")
-	cmd.Flags().StringVar(&bulkStatus, "status", "", "Filter by status")
+	cmd.Flags().Var((*statusValue)(&bulkStatus), "status", "Filter by status ("+statusNames()+")")
 
-	cmd.Run = func(cmd *cobra.Command, args []string) {
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		tasks, err := store.LoadAllTasks()
 		if err != nil {
-			styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Error loading tasks: %v", err))
-			fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-			return
+			return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
+		}
+		now := time.Now()
+		q, err := parseQuery(bulkQuery, now)
+		if err != nil {
+			return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid query: %v", err))
+		}
+		if bulkTag != "" {
+			q.add("tag", ":", bulkTag, now)
+		}
+		if bulkStatus != "" {
+			q.add("status", ":", bulkStatus, now)
 		}
 		var toProcess []*TaskWithPath
-		for _, taskList := range tasks {
-			for _, t := range taskList {
-				match := true
-				if bulkQuery != "" {
-					q := strings.ToLower(bulkQuery)
-					if !(strings.Contains(strings.ToLower(t.Task.Title), q) ||
-						strings.Contains(strings.ToLower(t.Task.Description), q) ||
-						strings.Contains(strings.ToLower(strings.Join(t.Task.Tags, ",")), q) ||
-						strings.Contains(strings.ToLower(t.Topic), q)) {
-						match = false
+		tagged, indexed := store.(tagIndex)
+		if indexed && bulkQuery == "" && bulkStatus == "" && bulkTag != "" && !strings.Contains(bulkTag, "*") {
+			// A plain --tag is served from the index's tag map
+			toProcess, indexed = tagged.findTagged(tasks, bulkTag)
+		} else {
+			indexed = false
+		}
+		if !indexed {
+			byID := indexByID(tasks)
+			for _, taskList := range tasks {
+				for _, t := range taskList {
+					if q.matches(t, byID) {
+						toProcess = append(toProcess, t)
 					}
 				}
-				if bulkTag != "" && !containsString(t.Task.Tags, bulkTag) {
-					match = false
-				}
-				if bulkStatus != "" && string(t.Task.Status) != bulkStatus {
-					match = false
-				}
-				if match {
-					toProcess = append(toProcess, t)
-				}
 			}
 		}
 		if len(toProcess) == 0 {
-			styledErr := lipgloss.NewStyle().Foreground(cliError).Render("No matching tasks found.")
-			fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-			return
+			return fail(cmd, errTaskNotFound, "No matching tasks found.")
 		}
-		for _, t := range toProcess {
-			if bulkDelete {
-				os.Remove(t.FilePath)
-			}
-			if bulkComplete {
-				store.CompleteTask(t.Topic, t.Task.Title)
-			}
-			if bulkMove != "" {
-				newDir := store.basePath + "/tasks/" + bulkMove
-				os.MkdirAll(newDir, 0755)
-				newPath := newDir + "/" + filepath.Base(t.FilePath)
-				os.Rename(t.FilePath, newPath)
-			}
+		action := "bulk"
+		switch {
+		case bulkDelete:
+			action = "bulk delete"
+		case bulkComplete:
+			action = "bulk complete"
+		case bulkMove != "":
+			action = "bulk move to " + bulkMove
 		}
+		// One journal operation, so a single undo reverts the whole batch.
+		// A task that fails is reported and the rest are still processed.
+		var failed error
+		done := 0
+		journaled(store, fmt.Sprintf("%s (%d tasks)", action, len(toProcess)), func() error {
+			for _, t := range toProcess {
+				var err error
+				switch {
+				case bulkDelete:
+					err = store.DeleteTask(t)
+				case bulkComplete:
+					_, err = completeTask(store, t)
+				case bulkMove != "":
+					err = store.MoveTask(t, bulkMove)
+				}
+				if err != nil {
+					failed = fail(cmd, err, fmt.Sprintf("Error processing %s: %v", t.Task.Title, err))
+					continue
+				}
+				done++
+			}
+			return nil
+		})
 		successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
-		fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Bulk operation complete on %d tasks.", len(toProcess))))
+		fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Bulk operation complete on %d tasks.", done)))
+		return failed
 	}
 	return cmd
 }
@@ -2,47 +2,52 @@ package main
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
 
-func NewCompleteCmd(store *FileStore) *cobra.Command {
-	return &cobra.Command{
-		Use:   "complete [topic/]title",
+func NewCompleteCmd(store Storage) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "complete [topic/]title|id...",
 		Short: "Mark a task as completed",
-		Long:  "Mark a task as completed and archive it",
+		Long:  "Mark a task as completed and archive it. Pass several tasks, or - to read task IDs from stdin. With --all, every task matching the title is completed.",
 		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			input := strings.Join(args, " ")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tasks, err := store.LoadAllTasks()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
+			}
+			matches, err := resolveTargets(cmd, store, tasks, args)
+			if err != nil {
+				return fail(cmd, err, fmt.Sprintf("Error completing task: %s", lookupErrorMessage(err)))
+			}
 
-			// Parse topic and title
-			var topic, title string
-			if strings.Contains(input, "/") {
-				parts := strings.Split(input, "/")
-				if len(parts) >= 2 {
-					topic = strings.Join(parts[:len(parts)-1], "/")
-					title = parts[len(parts)-1]
-				} else {
-					title = input
+			byID := indexByID(tasks)
+			return eachTask(store, "complete", matches, func(found *TaskWithPath) error {
+				if open := openDependencies(found.Task, byID); len(open) > 0 {
+					warnStyle := lipgloss.NewStyle().Foreground(cliError)
+					fmt.Fprintln(cmd.ErrOrStderr(), warnStyle.Render(fmt.Sprintf("Warning: completing a task with open dependencies: %s", dependencyTitles(open))))
 				}
-			} else {
-				title = input
-			}
 
-			if err := store.CompleteTask(topic, title); err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Error completing task: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
-			}
+				next, err := completeTask(store, found)
+				if err != nil {
+					return fail(cmd, err, fmt.Sprintf("Error completing task: %v", err))
+				}
 
-			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
-			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Task completed and archived: %s", title)))
-			if topic != "" {
-				topicStyle := lipgloss.NewStyle().Foreground(cliSecondary)
-				fmt.Fprintln(cmd.OutOrStdout(), topicStyle.Render(fmt.Sprintf("Topic: %s", topic)))
-			}
+				successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+				fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Task completed and archived: %s", found.Task.Title)))
+				if found.Topic != "" {
+					topicStyle := lipgloss.NewStyle().Foreground(cliSecondary)
+					fmt.Fprintln(cmd.OutOrStdout(), topicStyle.Render(fmt.Sprintf("Topic: %s", found.Topic)))
+				}
+				if next != nil {
+					fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Next occurrence due: %s", formatDate(next.Due))))
+				}
+				return nil
+			})
 		},
 	}
+	cmd.Flags().Bool("all", false, "Complete every task matching the title")
+	return cmd
 }
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,6 +19,13 @@ type Config struct {
 	DefaultStatus string   `yaml:"default_status"`
 	Tags          []string `yaml:"tags"`
 	ShowWelcome   *bool    `yaml:"show_welcome,omitempty"`
+	// Statuses defines the task workflow; empty uses the built-in one.
+	Statuses []StatusDef `yaml:"statuses,omitempty"`
+	// Fields declares custom task fields.
+	Fields []FieldDef `yaml:"fields,omitempty"`
+	// Views are saved queries, by name. The local config adds to the
+	// global one's and replaces views of the same name.
+	Views map[string]View `yaml:"views,omitempty"`
 }
 
 func getConfigPaths() (global, local string) {
@@ -44,6 +53,89 @@ func loadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// loadConfigFile reads only the global or only the local config, for
+// changes that must not copy the other one's settings into it.
+func loadConfigFile(global bool) (*Config, error) {
+	path := configPath(global)
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// saveConfigKey sets one top-level key of the global or local config file
+// and leaves the rest of the file as it is, so settings the file does not
+// have are not written as empty values over the other config's. A nil
+// value removes the key.
+func saveConfigKey(global bool, key string, value interface{}) error {
+	path := configPath(global)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: not a YAML mapping", path)
+	}
+
+	var node *yaml.Node
+	if value != nil {
+		node = &yaml.Node{}
+		if err := node.Encode(value); err != nil {
+			return err
+		}
+	}
+	found := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != key {
+			continue
+		}
+		if node == nil {
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+		} else {
+			root.Content[i+1] = node
+		}
+		found = true
+		break
+	}
+	if !found && node != nil {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, node)
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, out, 0644)
+}
+
+// configPath returns the path of the global or the local config file.
+func configPath(global bool) string {
+	globalPath, localPath := getConfigPaths()
+	if global {
+		return globalPath
+	}
+	return localPath
+}
+
 func saveConfig(cfg *Config, global bool) error {
 	globalPath, localPath := getConfigPaths()
 	path := localPath
@@ -52,7 +144,7 @@ func saveConfig(cfg *Config, global bool) error {
 	}
 	os.MkdirAll(filepath.Dir(path), 0755)
 	data, _ := yaml.Marshal(cfg)
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data, 0644)
 }
 
 func NewConfigCmd() *cobra.Command {
@@ -65,10 +157,11 @@ func NewConfigCmd() *cobra.Command {
 	cmd.AddCommand(&cobra.Command{
 		Use:   "show",
 		Short: "Show effective config",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, _ := loadConfig()
 			data, _ := yaml.Marshal(cfg)
 			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return nil
 		},
 	})
 
@@ -76,39 +169,33 @@ func NewConfigCmd() *cobra.Command {
 		Use:   "set [key] [value] [--global]",
 		Short: "Set a config value",
 		Args:  cobra.MinimumNArgs(2),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			key, value := args[0], args[1]
 			global, _ := cmd.Flags().GetBool("global")
-			cfg, _ := loadConfig()
+			// Only the key is written, so the file does not pick up the
+			// other config's settings
+			var setting interface{} = value
 			switch key {
-			case "default_sort":
-				cfg.DefaultSort = value
-			case "theme":
-				cfg.Theme = value
+			case "default_sort", "theme":
 			case "default_status":
-				cfg.DefaultStatus = value
+				if !TaskStatus(value).isValid() {
+					return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid default_status %q (valid: %s).", value, statusNames()))
+				}
 			case "tags":
-				cfg.Tags = strings.Split(value, ",")
+				setting = strings.Split(value, ",")
 			case "show_welcome":
-				if value == "true" {
-					b := true
-					cfg.ShowWelcome = &b
-				} else if value == "false" {
-					b := false
-					cfg.ShowWelcome = &b
-				} else {
-					fmt.Fprintln(cmd.ErrOrStderr(), lipgloss.NewStyle().Foreground(cliError).Render("show_welcome must be true or false."))
-					return
+				if value != "true" && value != "false" {
+					return fail(cmd, errInvalidInput, "show_welcome must be true or false.")
 				}
+				setting = value == "true"
 			default:
-				fmt.Fprintln(cmd.ErrOrStderr(), lipgloss.NewStyle().Foreground(cliError).Render("Unknown config key."))
-				return
+				return fail(cmd, errInvalidInput, "Unknown config key.")
 			}
-			if err := saveConfig(cfg, global); err != nil {
-				fmt.Fprintln(cmd.ErrOrStderr(), lipgloss.NewStyle().Foreground(cliError).Render("Failed to save config."))
-				return
+			if err := saveConfigKey(global, key, setting); err != nil {
+				return fail(cmd, err, "Failed to save config.")
 			}
 			fmt.Fprintln(cmd.OutOrStdout(), lipgloss.NewStyle().Foreground(cliPrimary).Bold(true).Render("Config updated."))
+			return nil
 		},
 	})
 	cmd.PersistentFlags().Bool("global", false, "Affect global config instead of local")
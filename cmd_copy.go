@@ -2,80 +2,39 @@ package main
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
 
 // Copy a task to a new topic (duplicate)
-func NewCopyCmd(store *FileStore) *cobra.Command {
+func NewCopyCmd(store Storage) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "copy [topic/]title newtopic",
+		Use:   "copy [topic/]title|id... newtopic",
 		Short: "Copy a task to a new topic",
-		Long:  "Copy a task to a new topic (creates a duplicate).",
+		Long:  "Copy tasks to a new topic (creates duplicates). Pass several tasks, or - to read task IDs from stdin.",
 		Args:  cobra.MinimumNArgs(2),
-		Run: func(cmd *cobra.Command, args []string) {
-			input := args[0]
-			newTopic := args[1]
-			var topic, title string
-			if strings.Contains(input, "/") {
-				parts := strings.Split(input, "/")
-				if len(parts) >= 2 {
-					topic = strings.Join(parts[:len(parts)-1], "/")
-					title = parts[len(parts)-1]
-				} else {
-					title = input
-				}
-			} else {
-				title = input
-			}
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newTopic := args[len(args)-1]
 
 			tasks, err := store.LoadAllTasks()
 			if err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Error loading tasks: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
-			}
-			var found *TaskWithPath
-			for _, taskList := range tasks {
-				for _, t := range taskList {
-					if t.Task.Title == title && t.Topic == topic {
-						found = t
-					}
-				}
-			}
-			if found == nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render("Task not found.")
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
 			}
-
-			// Copy file to new topic directory
-			oldPath := found.FilePath
-			newDir := filepath.Join(store.basePath, TasksDir, newTopic)
-			if err := os.MkdirAll(newDir, 0755); err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Failed to create new topic directory: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
-			}
-			newPath := filepath.Join(newDir, filepath.Base(oldPath))
-			inputData, err := os.ReadFile(oldPath)
+			matches, err := resolveTargets(cmd, store, tasks, args[:len(args)-1])
 			if err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Failed to read task: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
+				return fail(cmd, err, lookupErrorMessage(err))
 			}
-			if err := os.WriteFile(newPath, inputData, 0644); err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Failed to copy task: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
-			}
-			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
-			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Task copied to topic: %s", newTopic)))
+			return eachTask(store, "copy", matches, func(found *TaskWithPath) error {
+				if _, err := store.CopyTask(found, newTopic); err != nil {
+					return fail(cmd, err, fmt.Sprintf("Failed to copy task: %v", err))
+				}
+				successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+				fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(withTitle(fmt.Sprintf("Task copied to topic: %s", newTopic), found, len(matches) > 1)))
+				return nil
+			})
 		},
 	}
+	cmd.Flags().Bool("all", false, "Copy every task matching the title")
 	return cmd
 }
@@ -2,61 +2,38 @@ package main
 
 import (
 	"fmt"
-	"os"
-	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
 
-func NewDeleteCmd(store *FileStore) *cobra.Command {
+func NewDeleteCmd(store Storage) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete [topic/]title",
+		Use:   "delete [topic/]title|id...",
 		Short: "Delete a task",
-		Long:  "Delete a task by topic/title.",
+		Long:  "Move a task to the trash by topic/title or ID. Pass several tasks, or - to read task IDs from stdin. Restore it with tada trash restore. With --all, every task matching the title is deleted.",
 		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			input := strings.Join(args, " ")
-			var topic, title string
-			if strings.Contains(input, "/") {
-				parts := strings.Split(input, "/")
-				if len(parts) >= 2 {
-					topic = strings.Join(parts[:len(parts)-1], "/")
-					title = parts[len(parts)-1]
-				} else {
-					title = input
-				}
-			} else {
-				title = input
-			}
-
+		RunE: func(cmd *cobra.Command, args []string) error {
 			tasks, err := store.LoadAllTasks()
 			if err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Error loading tasks: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
-			}
-			var found *TaskWithPath
-			for _, taskList := range tasks {
-				for _, t := range taskList {
-					if t.Task.Title == title && t.Topic == topic {
-						found = t
-					}
-				}
-			}
-			if found == nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render("Task not found.")
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
 			}
-			if err := os.Remove(found.FilePath); err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Failed to delete: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
+			matches, err := resolveTargets(cmd, store, tasks, args)
+			if err != nil {
+				return fail(cmd, err, lookupErrorMessage(err))
 			}
-			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
-			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render("Task deleted."))
+			return eachTask(store, "delete", matches, func(found *TaskWithPath) error {
+				if err := store.DeleteTask(found); err != nil {
+					return fail(cmd, err, fmt.Sprintf("Failed to delete: %v", err))
+				}
+				successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+				fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(withTitle("Task deleted.", found, len(matches) > 1)))
+				hintStyle := lipgloss.NewStyle().Foreground(cliMuted)
+				fmt.Fprintln(cmd.OutOrStdout(), hintStyle.Render(fmt.Sprintf("Restore it with: tada trash restore %s", found.Task.ID)))
+				return nil
+			})
 		},
 	}
+	cmd.Flags().Bool("all", false, "Delete every task matching the title")
 	return cmd
 }
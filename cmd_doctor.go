@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// NewDoctorCmd checks the .tada directory for problems and optionally
+// repairs them.
+func NewDoctorCmd(store Storage) *cobra.Command {
+	var outputFormat string
+	var fix bool
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the task files for problems",
+		Long: `Scan every task file, active, archived and deleted, and report problems
+with their file path: malformed files, unknown statuses, missing or duplicate
+IDs, duplicate titles within a topic, done tasks that were not archived, file
+names that no longer match the title, empty topic directories and leftover
+temporary files. With --fix, every problem that has a safe repair is fixed.
+Exits non-zero if any problem remains.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs, ok := store.(*FileStore)
+			if !ok {
+				return fail(cmd, errors.ErrUnsupported, "Error: doctor only works with task files on disk")
+			}
+			report, err := fs.Diagnose()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error checking tasks: %v", err))
+			}
+			if fix {
+				journaled(fs, "doctor --fix", func() error {
+					report.Repair()
+					return nil
+				})
+			}
+
+			if outputFormat == "json" {
+				if report.Problems == nil {
+					report.Problems = []*problem{}
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				enc.Encode(report)
+				return problemsRemain(cmd, report)
+			}
+
+			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+			if len(report.Problems) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("No problems found in %d task files.", report.Checked)))
+				return nil
+			}
+
+			pathStyle := lipgloss.NewStyle().Bold(true)
+			kindStyle := lipgloss.NewStyle().Foreground(cliError)
+			fixedStyle := lipgloss.NewStyle().Foreground(cliSecondary)
+			mutedStyle := lipgloss.NewStyle().Foreground(cliMuted)
+			fixable, fixed := 0, 0
+			for _, p := range report.Problems {
+				line := fmt.Sprintf("%s: %s %s", pathStyle.Render(fs.displayPath(p.Path)), kindStyle.Render(p.Kind), p.Reason)
+				switch {
+				case p.Fixed:
+					fixed++
+					line += " " + fixedStyle.Render("(fixed)")
+				case p.Error != "":
+					line += " " + kindStyle.Render(fmt.Sprintf("(fix failed: %s)", p.Error))
+				case p.Fixable:
+					fixable++
+					line += " " + mutedStyle.Render("(fixable)")
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), line)
+			}
+
+			summary := fmt.Sprintf("\n%d problems in %d task files", len(report.Problems), report.Checked)
+			switch {
+			case fix:
+				summary += fmt.Sprintf(", %d fixed.", fixed)
+			case fixable > 0:
+				summary += fmt.Sprintf(", %d can be fixed with --fix.", fixable)
+			default:
+				summary += "."
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), mutedStyle.Render(summary))
+			return problemsRemain(cmd, report)
+		},
+	}
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format: json or pretty (default)")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Apply safe repairs")
+	return cmd
+}
+
+// problemsRemain fails the command if the report has unfixed problems, so
+// scripts can tell a healthy .tada directory from one that needs attention.
+func problemsRemain(cmd *cobra.Command, report *doctorReport) error {
+	if n := report.remaining(); n > 0 {
+		return fail(cmd, errProblemsRemain, fmt.Sprintf("%d problems remain.", n))
+	}
+	return nil
+}
@@ -2,8 +2,7 @@ package main
 
 import (
 	"fmt"
-	"os"
-	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -12,78 +11,130 @@ import (
 // Use CLI color palette from cmd_list.go
 // (Removed local color palette to resolve redeclaration errors)
 
-func NewEditCmd(store *FileStore) *cobra.Command {
+func NewEditCmd(store Storage) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "edit [topic/]title",
+		Use:   "edit [topic/]title|id...",
 		Short: "Edit a task",
-		Long:  "Edit a task's fields by topic/title.",
+		Long:  "Edit a task's fields by topic/title or ID. Pass several tasks, or - to read task IDs from stdin. With --all, every task matching the title is edited.",
 		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			input := strings.Join(args, " ")
-			var topic, title string
-			if strings.Contains(input, "/") {
-				parts := strings.Split(input, "/")
-				if len(parts) >= 2 {
-					topic = strings.Join(parts[:len(parts)-1], "/")
-					title = parts[len(parts)-1]
-				} else {
-					title = input
-				}
-			} else {
-				title = input
-			}
-
+		RunE: func(cmd *cobra.Command, args []string) error {
 			description, _ := cmd.Flags().GetString("description")
 			priority, _ := cmd.Flags().GetInt("priority")
 			tags, _ := cmd.Flags().GetStringSlice("tags")
 			status, _ := cmd.Flags().GetString("status")
+			dueFlag, _ := cmd.Flags().GetString("due")
+			scheduledFlag, _ := cmd.Flags().GetString("scheduled")
+			recur, _ := cmd.Flags().GetString("recur")
+			dependsOn, _ := cmd.Flags().GetStringSlice("depends-on")
+			fieldFlags, _ := cmd.Flags().GetStringArray("field")
 
-			tasks, err := store.LoadAllTasks()
+			now := time.Now()
+			fields, err := parseFieldFlags(fieldFlags, now)
 			if err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Error loading tasks: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --field: %v", err))
 			}
-			var found *TaskWithPath
-			for _, taskList := range tasks {
-				for _, t := range taskList {
-					if t.Task.Title == title && t.Topic == topic {
-						found = t
-					}
-				}
-			}
-			if found == nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render("Task not found.")
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
+			due, clearDue, err := parseDateFlag(dueFlag, now)
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --due: %v", err))
 			}
-
-			if description != "" {
-				found.Task.Description = description
+			scheduled, clearScheduled, err := parseDateFlag(scheduledFlag, now)
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --scheduled: %v", err))
 			}
-			if cmd.Flags().Changed("priority") {
-				found.Task.Priority = priority
+			if recur != "" && recur != "none" {
+				if _, err := parseRecurrence(recur); err != nil {
+					return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --recur: %v", err))
+				}
 			}
-			if len(tags) > 0 {
-				found.Task.Tags = tags
+
+			tasks, err := store.LoadAllTasks()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
 			}
-			if status != "" {
-				found.Task.Status = TaskStatus(status)
+			matches, err := resolveTargets(cmd, store, tasks, args)
+			if err != nil {
+				return fail(cmd, err, lookupErrorMessage(err))
 			}
 
-			content := store.taskToMarkdown(found.Task)
-			if err := os.WriteFile(found.FilePath, []byte(content), 0644); err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Failed to save: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
-			}
-			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
-			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render("Task updated."))
+			return eachTask(store, "edit", matches, func(found *TaskWithPath) error {
+				generated := found.Task.hasGeneratedBody()
+				wasClosed := found.Task.Status.isClosed()
+				if description != "" {
+					found.Task.Description = description
+				}
+				if cmd.Flags().Changed("priority") {
+					found.Task.Priority = priority
+				}
+				if len(tags) > 0 {
+					found.Task.Tags = tags
+				}
+				if status != "" {
+					if err := checkTransition(found.Task.Status, TaskStatus(status)); err != nil {
+						return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --status: %v", err))
+					}
+					found.Task.setStatus(TaskStatus(status))
+				}
+				if due != nil || clearDue {
+					found.Task.Due = due
+				}
+				if scheduled != nil || clearScheduled {
+					found.Task.Scheduled = scheduled
+				}
+				found.Task.setFieldValues(fields)
+				if recur == "none" {
+					found.Task.Recur = ""
+				} else if recur != "" {
+					found.Task.Recur = recur
+				}
+				if len(dependsOn) == 1 && dependsOn[0] == "none" {
+					found.Task.DependsOn = nil
+				} else if len(dependsOn) > 0 {
+					ids, err := resolveDependencies(tasks, dependsOn)
+					if err == nil {
+						err = checkDependencyCycle(indexByID(tasks), found.Task.ID, ids)
+					}
+					if err != nil {
+						return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --depends-on: %v", err))
+					}
+					found.Task.DependsOn = ids
+				}
+
+				if generated {
+					// Regenerate the heading and description; hand-written bodies are kept
+					found.Task.Body = ""
+				}
+
+				successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+				// Closing a task archives it, as tada complete does
+				if !wasClosed && found.Task.Status.isClosed() {
+					next, err := completeTask(store, found)
+					if err != nil {
+						return fail(cmd, err, fmt.Sprintf("Failed to save: %v", err))
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(withTitle("Task updated and archived.", found, len(matches) > 1)))
+					if next != nil {
+						fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Next occurrence due: %s", formatDate(next.Due))))
+					}
+					return nil
+				}
+
+				if err := store.UpdateTask(found); err != nil {
+					return fail(cmd, err, fmt.Sprintf("Failed to save: %v", err))
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(withTitle("Task updated.", found, len(matches) > 1)))
+				return nil
+			})
 		},
 	}
 	cmd.Flags().StringP("description", "d", "", "Task description")
-	cmd.Flags().IntP("priority", "p", 3, "Task priority (0, 1, 2, ...)")
+	cmd.Flags().VarP(newPriorityValue(3), "priority", "p", "Task priority (0, 1, 2, ...)")
 	cmd.Flags().StringSliceP("tags", "t", []string{}, "Task tags")
-	cmd.Flags().String("status", "", "Task status (todo, in-progress, done, cancelled, paused)")
+	cmd.Flags().Var(new(statusValue), "status", "Task status ("+statusNames()+")")
+	cmd.Flags().String("due", "", "Due date (e.g. tomorrow, \"next fri\", 2026-11-01, or none to clear)")
+	cmd.Flags().String("scheduled", "", "Scheduled date (same formats as --due, or none to clear)")
+	cmd.Flags().String("recur", "", "Repeat rule (same formats as add --recur, or none to stop repeating)")
+	cmd.Flags().StringSlice("depends-on", []string{}, "Tasks this one depends on, by ID or topic/title (replaces the list; none to clear)")
+	cmd.Flags().StringArray("field", []string{}, "Set a custom field as key=value, or key= to clear it (repeatable)")
+	cmd.Flags().Bool("all", false, "Edit every task matching the title")
 	return cmd
 }
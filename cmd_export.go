@@ -12,42 +12,56 @@ import (
 )
 
 func NewExportCmd(store Storage) *cobra.Command {
-	var format, output string
+	var format, output, search string
 	cmd := &cobra.Command{
 		Use:   "export",
 		Short: "Export all tasks to a single file (csv, json, or md)",
+		Long:  "Export all tasks, or those matching --search, to a single file (csv, json, or md).",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var write func(*os.File, map[string][]*TaskWithPath) error
+			switch format {
+			case "json":
+				write = func(out *os.File, tasks map[string][]*TaskWithPath) error {
+					enc := json.NewEncoder(out)
+					enc.SetIndent("", "  ")
+					return enc.Encode(flattenTasks(tasks))
+				}
+			case "csv":
+				write = exportCSV
+			case "md", "markdown":
+				write = exportMarkdown
+			default:
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --format: unsupported format %q (use csv, json or md).", format))
+			}
+
 			tasks, err := store.LoadAllTasks()
 			if err != nil {
-				return fmt.Errorf("failed to load tasks: %w", err)
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
+			}
+			q, err := parseQuery(search, time.Now())
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid query: %v", err))
 			}
+			tasks = q.filter(tasks)
 			var out *os.File
 			if output == "" || output == "-" {
 				out = os.Stdout
 			} else {
 				out, err = os.Create(output)
 				if err != nil {
-					return fmt.Errorf("failed to create output file: %w", err)
+					return fail(cmd, err, fmt.Sprintf("Failed to create output file: %v", err))
 				}
 				defer out.Close()
 			}
-			switch format {
-			case "json":
-				all := flattenTasks(tasks)
-				enc := json.NewEncoder(out)
-				enc.SetIndent("", "  ")
-				return enc.Encode(all)
-			case "csv":
-				return exportCSV(out, tasks)
-			case "md", "markdown":
-				return exportMarkdown(out, tasks)
-			default:
-				return fmt.Errorf("unsupported format: %s", format)
+			if err := write(out, tasks); err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error writing export: %v", err))
 			}
+			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&format, "format", "f", "json", "Export format: csv, json, md")
 	cmd.Flags().StringVarP(&output, "output", "o", "-", "Output file (default: stdout)")
+	cmd.Flags().StringVarP(&search, "search", "q", "", "Only export tasks matching this query, as in tada list --search")
 	return cmd
 }
 
@@ -64,24 +78,42 @@ func flattenTasks(tasks map[string][]*TaskWithPath) []*Task {
 func exportCSV(out *os.File, tasks map[string][]*TaskWithPath) error {
 	w := csv.NewWriter(out)
 	defer w.Flush()
-	w.Write([]string{"Title", "Description", "Priority", "Status", "Tags", "CreatedAt", "CompletedAt"})
+	w.Write(csvHeader())
 	for _, list := range tasks {
 		for _, t := range list {
-			task := t.Task
-			w.Write([]string{
-				task.Title,
-				task.Description,
-				fmt.Sprintf("%d", task.Priority),
-				string(task.Status),
-				strings.Join(task.Tags, ","),
-				task.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-				formatCompletedAt(task.CompletedAt),
-			})
+			w.Write(csvRow(t.Task))
 		}
 	}
 	return w.Error()
 }
 
+// csvHeader is the header of a CSV export: the built-in columns followed
+// by one column per custom field declared in the config.
+func csvHeader() []string {
+	header := []string{"Title", "Description", "Priority", "Status", "Tags", "CreatedAt", "CompletedAt"}
+	for _, f := range customFields {
+		header = append(header, f.Name)
+	}
+	return header
+}
+
+// csvRow is the CSV record of a task, matching csvHeader.
+func csvRow(task *Task) []string {
+	row := []string{
+		task.Title,
+		task.Description,
+		fmt.Sprintf("%d", task.Priority),
+		string(task.Status),
+		strings.Join(task.Tags, ","),
+		task.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		formatCompletedAt(task.CompletedAt),
+	}
+	for _, f := range customFields {
+		row = append(row, task.Fields[f.Name])
+	}
+	return row
+}
+
 func formatCompletedAt(t *time.Time) string {
 	if t == nil {
 		return ""
@@ -133,17 +165,9 @@ func ExportTasksToFile(tasks []*TaskWithPath, format, filePath string) error {
 	case "csv":
 		w := csv.NewWriter(out)
 		defer w.Flush()
-		w.Write([]string{"Title", "Description", "Priority", "Status", "Tags", "CreatedAt", "CompletedAt"})
+		w.Write(csvHeader())
 		for _, t := range tasks {
-			w.Write([]string{
-				t.Task.Title,
-				t.Task.Description,
-				fmt.Sprintf("%d", t.Task.Priority),
-				string(t.Task.Status),
-				strings.Join(t.Task.Tags, ","),
-				t.Task.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-				formatCompletedAt(t.Task.CompletedAt),
-			})
+			w.Write(csvRow(t.Task))
 		}
 		return w.Error()
 	case "md", "markdown":
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/csv"
 	"encoding/json"
+	"io"
 	"os"
 	"strings"
 	"testing"
@@ -87,10 +88,14 @@ func TestExportCommand_InvalidFormat(t *testing.T) {
 
 	cmd := NewExportCmd(store)
 	cmd.SetArgs([]string{"--format", "invalid", "--output", outputFile})
+	cmd.SetErr(io.Discard)
 	err := cmd.Execute()
-	if err == nil || !strings.Contains(err.Error(), "unsupported format") {
+	if err == nil || !strings.Contains(err.Error(), "unsupported format") || exitCode(err) != exitInvalidInput {
 		t.Errorf("expected invalid format error, got: %v", err)
 	}
+	if _, statErr := os.Stat(outputFile); !os.IsNotExist(statErr) {
+		t.Errorf("expected no output file for an invalid format")
+	}
 }
 
 // newTestStoreWithTasks is a helper for test setup
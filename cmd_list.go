@@ -3,15 +3,14 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"path/filepath"
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/lithammer/fuzzysearch/fuzzy"
 	"github.com/spf13/cobra"
 )
 
@@ -23,7 +22,28 @@ var (
 	cliMuted     = lipgloss.Color("8")  // gray
 )
 
-func NewListCmd(store *FileStore, cfg *Config) *cobra.Command {
+func NewListCmd(store Storage, cfg *Config) *cobra.Command {
+	cmd := newTaskListCmd(cfg, func(cmd *cobra.Command) (map[string][]*TaskWithPath, error) {
+		tasks, err := store.LoadAllTasks()
+		if includeArchived, _ := cmd.Flags().GetBool("include-archived"); err != nil || !includeArchived {
+			return tasks, err
+		}
+		archived, err := store.LoadArchivedTasks()
+		for topic, list := range archived {
+			tasks[topic] = append(tasks[topic], list...)
+		}
+		return tasks, err
+	})
+	cmd.Use = "list"
+	cmd.Short = "List tasks"
+	cmd.Long = "List all tasks with optional filtering, searching, and sorting"
+	cmd.Flags().Bool("include-archived", false, "Also list archived tasks")
+	return cmd
+}
+
+// newTaskListCmd builds a listing command over the tasks returned by load,
+// with the filter, sort and output flags shared by list and archive list.
+func newTaskListCmd(cfg *Config, load func(cmd *cobra.Command) (map[string][]*TaskWithPath, error)) *cobra.Command {
 	var outputFormat string
 	var fuzzyFlag bool
 	var defaultSort = "created"
@@ -31,64 +51,70 @@ func NewListCmd(store *FileStore, cfg *Config) *cobra.Command {
 		defaultSort = cfg.DefaultSort
 	}
 	cmd := &cobra.Command{
-		Use:   "list",
-		Short: "List tasks",
-		Long:  "List all tasks with optional filtering, searching, and sorting",
-		Run: func(cmd *cobra.Command, args []string) {
-			tasks, err := store.LoadAllTasks()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tasks, err := load(cmd)
 			if err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Error loading tasks: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
-			}
-
-			// Filter by status if specified
-			status, _ := cmd.Flags().GetString("status")
-			if status != "" {
-				filtered := make(map[string][]*TaskWithPath)
-				for path, taskList := range tasks {
-					for _, task := range taskList {
-						if string(task.Task.Status) == status {
-							filtered[path] = append(filtered[path], task)
-						}
-					}
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
+			}
+
+			// A saved view supplies a query and sort order; flags add to it
+			search, _ := cmd.Flags().GetString("search")
+			sortBy, _ := cmd.Flags().GetString("sort")
+			if name, _ := cmd.Flags().GetString("view"); name != "" {
+				view, ok := cfg.view(name)
+				if !ok {
+					return fail(cmd, errInvalidInput, fmt.Sprintf("Unknown view %q. See tada view list.", name))
 				}
-				tasks = filtered
-			}
-
-			// Search by query if specified (now includes tags and topic)
-			query, _ := cmd.Flags().GetString("search")
-			if query != "" {
-				filtered := make(map[string][]*TaskWithPath)
-				q := strings.ToLower(query)
-				for path, taskList := range tasks {
-					for _, task := range taskList {
-						match := false
-						if fuzzyFlag {
-							if fuzzy.MatchFold(q, strings.ToLower(task.Task.Title)) ||
-								fuzzy.MatchFold(q, strings.ToLower(task.Task.Description)) ||
-								fuzzy.MatchFold(q, strings.ToLower(strings.Join(task.Task.Tags, ","))) ||
-								fuzzy.MatchFold(q, strings.ToLower(path)) {
-								match = true
-							}
-						} else {
-							if strings.Contains(strings.ToLower(task.Task.Title), q) ||
-								strings.Contains(strings.ToLower(task.Task.Description), q) ||
-								strings.Contains(strings.ToLower(strings.Join(task.Task.Tags, ",")), q) ||
-								strings.Contains(strings.ToLower(path), q) {
-								match = true
-							}
-						}
-						if match {
-							filtered[path] = append(filtered[path], task)
-						}
-					}
+				search = strings.TrimSpace(view.Query + " " + search)
+				if view.Sort != "" && !cmd.Flags().Changed("sort") {
+					sortBy = view.Sort
+				}
+			}
+
+			// The filter flags are shorthands for terms of the --search query
+			now := time.Now()
+			q, err := parseQuery(search, now)
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid query: %v", err))
+			}
+			q.fuzzy = fuzzyFlag
+			if status, _ := cmd.Flags().GetString("status"); status != "" {
+				q.add("status", ":", status, now)
+			}
+
+			// Filter by custom fields; key= matches tasks without the field
+			fieldFlags, _ := cmd.Flags().GetStringArray("field")
+			want, err := parseFieldFlags(fieldFlags, now)
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --field: %v", err))
+			}
+			for key, value := range want {
+				if err := q.add(key, "=", value, now); err != nil {
+					return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --field: %v", err))
 				}
-				tasks = filtered
 			}
 
+			if ready, _ := cmd.Flags().GetBool("ready"); ready {
+				q.add("is", ":", "ready", now)
+			}
+			if overdue, _ := cmd.Flags().GetBool("overdue"); overdue {
+				q.add("is", ":", "overdue", now)
+			}
+			for _, flag := range []struct{ name, op string }{{"due-before", "<"}, {"due-after", ">"}} {
+				value, _ := cmd.Flags().GetString(flag.name)
+				if value == "" {
+					continue
+				}
+				if err := q.add("due", flag.op, value, now); err != nil {
+					return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --%s: %v", flag.name, err))
+				}
+			}
+
+			// Dependencies are resolved against every loaded task, before filtering
+			byID := indexByID(tasks)
+			tasks = q.filter(tasks)
+
 			// Sort tasks
-			sortBy, _ := cmd.Flags().GetString("sort")
 			if sortBy == "" {
 				sortBy = defaultSort
 			}
@@ -96,41 +122,50 @@ func NewListCmd(store *FileStore, cfg *Config) *cobra.Command {
 				sortTasks(tasks[path], sortBy)
 			}
 
+			// Bare IDs, one per line, for piping into other commands. Tasks
+			// without an ID yet have nothing to print.
+			if ids, _ := cmd.Flags().GetBool("ids"); ids {
+				for _, taskList := range tasks {
+					for _, taskWithPath := range taskList {
+						if taskWithPath.Task.ID == "" {
+							continue
+						}
+						fmt.Fprintln(cmd.OutOrStdout(), taskWithPath.Task.ID)
+					}
+				}
+				return nil
+			}
+
 			// Simple output flag
 			simple, _ := cmd.Flags().GetBool("simple")
 
 			if simple {
 				for _, taskList := range tasks {
 					for _, taskWithPath := range taskList {
-						id := ""
-						base := filepath.Base(taskWithPath.FilePath)
-						if len(base) > 5 {
-							id = base[:5]
-						}
 						statusStyle := lipgloss.NewStyle().Foreground(cliPrimary)
 						titleStyle := lipgloss.NewStyle().Bold(true)
-						fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", id, titleStyle.Render(taskWithPath.Task.Title), statusStyle.Render(string(taskWithPath.Task.Status)))
+						fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", taskWithPath.Task.ID, titleStyle.Render(taskWithPath.Task.Title), statusStyle.Render(string(taskWithPath.Task.Status)))
 					}
 				}
-				return
+				return nil
 			}
 
 			if outputFormat == "json" {
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
 				enc.Encode(tasks)
-				return
+				return nil
 			}
 			if outputFormat == "yaml" {
 				enc := yaml.NewEncoder(cmd.OutOrStdout())
 				enc.Encode(tasks)
-				return
+				return nil
 			}
 
 			// Pretty output
 			headStyle := lipgloss.NewStyle().Bold(true).Foreground(cliPrimary)
 			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
-			fmt.Fprintln(w, headStyle.Render("ID\tTOPIC\tTITLE\tPRIORITY\tSTATUS\tTAGS\tCREATED"))
+			fmt.Fprintln(w, headStyle.Render("ID\tTOPIC\tTITLE\tPRIORITY\tSTATUS\tSUBTASKS\tTAGS\tDUE\tCREATED"))
 
 			for topic, taskList := range tasks {
 				if len(taskList) == 0 {
@@ -149,38 +184,61 @@ func NewListCmd(store *FileStore, cfg *Config) *cobra.Command {
 						tagsStr = "-"
 					}
 					priority := fmt.Sprintf("%d", task.Priority)
-					id := ""
-					base := filepath.Base(taskWithPath.FilePath)
-					if len(base) > 5 {
-						id = base[:5]
-					}
-					statusStyle := lipgloss.NewStyle().Foreground(cliPrimary)
+					statusStyle := task.Status.style(cliPrimary)
 					titleStyle := lipgloss.NewStyle().Bold(true)
 					tagsStyle := lipgloss.NewStyle().Foreground(cliSecondary)
-					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-						id,
+					progress := task.progress()
+					if progress == "" {
+						progress = "-"
+					}
+					statusText := string(task.Status)
+					if len(openDependencies(task, byID)) > 0 {
+						statusText += " (blocked)"
+					}
+					dueStyle := lipgloss.NewStyle()
+					if task.isOverdue(now) {
+						dueStyle = dueStyle.Foreground(cliError).Bold(true)
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+						task.ID,
 						topicDisplay,
 						titleStyle.Render(task.Title),
 						priority,
-						statusStyle.Render(string(task.Status)),
+						statusStyle.Render(statusText),
+						progress,
 						tagsStyle.Render(tagsStr),
+						dueStyle.Render(formatDate(task.Due)),
 						task.CreatedAt.Format("2006-01-02 15:04"),
 					)
 				}
 			}
 			w.Flush()
+			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format: json, yaml, or pretty (default)")
-	cmd.Flags().BoolVar(&fuzzyFlag, "fuzzy", false, "Enable fuzzy search for --search")
-	cmd.Flags().StringP("status", "s", "", "Filter by status (todo, in-progress, done, cancelled, paused)")
-	cmd.Flags().String("sort", defaultSort, "Sort by: created, priority, title, status")
+	cmd.Flags().BoolVar(&fuzzyFlag, "fuzzy", false, "Match free text in --search fuzzily")
+	cmd.Flags().VarP(new(statusValue), "status", "s", "Filter by status ("+statusNames()+")")
+	cmd.Flags().String("sort", defaultSort, "Sort by: created, priority, title, status, due, or a custom field")
+	cmd.Flags().StringArray("field", []string{}, "Only show tasks with this custom field value, as key=value (repeatable; key= for unset)")
+	cmd.Flags().Bool("ready", false, "Only show open tasks with no open dependencies")
+	cmd.Flags().Bool("overdue", false, "Only show open tasks whose due date has passed")
+	cmd.Flags().String("due-before", "", "Only show tasks due before this date (e.g. \"next fri\", 2026-11-01)")
+	cmd.Flags().String("due-after", "", "Only show tasks due after this date")
 	cmd.Flags().Bool("simple", false, "Print simple output (id, title, status)")
-	cmd.Flags().StringP("search", "q", "", "Search for tasks by title, description, tags, or topic")
+	cmd.Flags().Bool("ids", false, "Print only task IDs, one per line (e.g. to pipe into tada complete -)")
+	cmd.Flags().StringP("search", "q", "", "Filter by a query such as 'tag:urgent priority<=2 deploy' (free text searches title, description, tags and topic)")
+	cmd.Flags().String("view", "", "Start from a view saved with tada view save")
 	return cmd
 }
 
 func sortTasks(tasks []*TaskWithPath, sortBy string) {
+	if f, ok := fieldDef(sortBy); ok {
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return f.less(tasks[i].Task.Fields[f.Name], tasks[j].Task.Fields[f.Name])
+		})
+		return
+	}
 	sort.Slice(tasks, func(i, j int) bool {
 		switch sortBy {
 		case "priority":
@@ -189,7 +247,15 @@ func sortTasks(tasks []*TaskWithPath, sortBy string) {
 		case "title":
 			return tasks[i].Task.Title < tasks[j].Task.Title
 		case "status":
-			return tasks[i].Task.Status < tasks[j].Task.Status
+			// In workflow order
+			return tasks[i].Task.Status.rank() < tasks[j].Task.Status.rank()
+		case "due":
+			// Tasks without a due date sort last
+			di, dj := tasks[i].Task.Due, tasks[j].Task.Due
+			if di == nil || dj == nil {
+				return di != nil && dj == nil
+			}
+			return di.Before(*dj)
 		default: // created
 			return tasks[i].Task.CreatedAt.Before(tasks[j].Task.CreatedAt)
 		}
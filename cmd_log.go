@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// logEntry is the JSON form of a journaled operation.
+type logEntry struct {
+	Seq     int       `json:"seq"`
+	Time    time.Time `json:"time"`
+	Summary string    `json:"summary"`
+	Undone  bool      `json:"undone"`
+	Files   []string  `json:"files"`
+}
+
+// NewLogCmd lists the changes recorded in the journal.
+func NewLogCmd(store Storage) *cobra.Command {
+	var outputFormat string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show the history of changes",
+		Long:  "List the changes recorded in the journal, newest first. Undone changes are marked and can be redone with tada redo.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			j, ok := store.(journal)
+			if !ok {
+				return fail(cmd, errors.ErrUnsupported, "Error: log is not supported by this store")
+			}
+			history, err := j.History()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error reading journal: %v", err))
+			}
+
+			// Newest first
+			entries := make([]logEntry, 0, len(history))
+			for i := len(history) - 1; i >= 0 && (limit <= 0 || len(entries) < limit); i-- {
+				e := history[i]
+				files := make([]string, 0, len(e.Changes))
+				for _, c := range e.Changes {
+					files = append(files, c.Path)
+				}
+				entries = append(entries, logEntry{Seq: e.Seq, Time: e.Time, Summary: e.Summary, Undone: e.Undone, Files: files})
+			}
+
+			if outputFormat == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				enc.Encode(entries)
+				return nil
+			}
+			if len(entries) == 0 {
+				mutedStyle := lipgloss.NewStyle().Foreground(cliMuted)
+				fmt.Fprintln(cmd.OutOrStdout(), mutedStyle.Render("No changes recorded yet."))
+				return nil
+			}
+
+			headStyle := lipgloss.NewStyle().Bold(true).Foreground(cliPrimary)
+			undoneStyle := lipgloss.NewStyle().Foreground(cliMuted)
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, headStyle.Render("#\tTIME\tCHANGE"))
+			for _, e := range entries {
+				summary := e.Summary
+				if e.Undone {
+					summary = undoneStyle.Render(summary + " (undone)")
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\n", e.Seq, e.Time.Local().Format("2006-01-02 15:04"), summary)
+			}
+			w.Flush()
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format: json or pretty (default)")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "Show at most this many changes (0 for all)")
+	return cmd
+}
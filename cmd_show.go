@@ -3,8 +3,9 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"io"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -13,84 +14,138 @@ import (
 )
 
 // Show a detailed view of a single task by topic/title or ID
-func NewShowCmd(store *FileStore) *cobra.Command {
+func NewShowCmd(store Storage) *cobra.Command {
+	cmd := newTaskShowCmd(store, store.LoadAllTasks)
+	cmd.Use = "show [topic/]title|id..."
+	cmd.Short = "Show details for a task"
+	cmd.Long = "Show a detailed view of a task by topic/title or ID. Pass several tasks, or - to read task IDs from stdin."
+	return cmd
+}
+
+// newTaskShowCmd builds a command that shows one of the tasks returned by
+// load, shared by show, archive show and trash show.
+func newTaskShowCmd(store Storage, load func() (map[string][]*TaskWithPath, error)) *cobra.Command {
 	var outputFormat string
 	cmd := &cobra.Command{
-		Use:   "show [topic/]title|id",
-		Short: "Show details for a task",
-		Long:  "Show a detailed view of a single task by topic/title or ID.",
-		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			input := strings.Join(args, " ")
-			var topic, title, id string
-			if len(args) == 1 && len(args[0]) == 5 {
-				id = args[0]
-			}
-			if id == "" {
-				if strings.Contains(input, "/") {
-					parts := strings.Split(input, "/")
-					if len(parts) >= 2 {
-						topic = strings.Join(parts[:len(parts)-1], "/")
-						title = parts[len(parts)-1]
-					} else {
-						title = input
-					}
-				} else {
-					title = input
-				}
-			}
-
-			tasks, err := store.LoadAllTasks()
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tasks, err := load()
 			if err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Error loading tasks: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
 			}
-			var found *TaskWithPath
-			for _, taskList := range tasks {
-				for _, t := range taskList {
-					base := filepath.Base(t.FilePath)
-					if (id != "" && strings.HasPrefix(base, id)) || (t.Task.Title == title && t.Topic == topic) {
-						found = t
-					}
-				}
+			matches, err := resolveTargets(cmd, store, tasks, args)
+			if err != nil {
+				return fail(cmd, err, lookupErrorMessage(err))
 			}
-			if found == nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render("Task not found.")
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
+			// A single task is printed as an object, several as a list
+			var shown interface{} = matches
+			if len(matches) == 1 {
+				shown = matches[0]
 			}
 
 			if outputFormat == "json" {
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
-				enc.Encode(found)
-				return
+				enc.Encode(shown)
+				return nil
 			}
 			if outputFormat == "yaml" {
 				enc := yaml.NewEncoder(cmd.OutOrStdout())
-				enc.Encode(found)
-				return
+				enc.Encode(shown)
+				return nil
 			}
 
-			// Pretty print task details
-			header := lipgloss.NewStyle().Bold(true).Foreground(cliPrimary).Render(found.Task.Title)
-			topicStyle := lipgloss.NewStyle().Foreground(cliSecondary)
-			meta := fmt.Sprintf("Topic: %s\nPriority: %d\nStatus: %s\nTags: %s\nCreated: %s",
-				found.Topic,
-				found.Task.Priority,
-				found.Task.Status,
-				strings.Join(found.Task.Tags, ", "),
-				found.Task.CreatedAt.Format("2006-01-02 15:04"),
-			)
-			fmt.Fprintln(cmd.OutOrStdout(), header)
-			fmt.Fprintln(cmd.OutOrStdout(), topicStyle.Render(meta))
-			if found.Task.Description != "" {
-				descStyle := lipgloss.NewStyle().Foreground(cliMuted)
-				fmt.Fprintln(cmd.OutOrStdout(), descStyle.Render("\n"+found.Task.Description))
+			for i, found := range matches {
+				if i > 0 {
+					fmt.Fprintln(cmd.OutOrStdout())
+				}
+				printTaskDetails(cmd.OutOrStdout(), found, tasks)
 			}
+			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format: json, yaml, or pretty (default)")
+	cmd.Flags().Bool("all", false, "Show every task matching the title")
 	return cmd
 }
+
+// printTaskDetails pretty prints a task; tasks resolves its dependencies.
+func printTaskDetails(w io.Writer, found *TaskWithPath, tasks map[string][]*TaskWithPath) {
+	header := lipgloss.NewStyle().Bold(true).Foreground(cliPrimary).Render(found.Task.Title)
+	topicStyle := lipgloss.NewStyle().Foreground(cliSecondary)
+	meta := fmt.Sprintf("ID: %s\nTopic: %s\nPriority: %d\nStatus: %s\nTags: %s\nCreated: %s",
+		found.Task.ID,
+		found.Topic,
+		found.Task.Priority,
+		found.Task.Status,
+		strings.Join(found.Task.Tags, ", "),
+		found.Task.CreatedAt.Format("2006-01-02 15:04"),
+	)
+	for _, key := range found.Task.fieldKeys() {
+		meta += fmt.Sprintf("\n%s: %s", key, found.Task.Fields[key])
+	}
+	if found.Task.UpdatedAt != nil {
+		meta += "\nUpdated: " + found.Task.UpdatedAt.Format("2006-01-02 15:04")
+	}
+	if found.Task.Due != nil {
+		meta += "\nDue: " + formatDate(found.Task.Due)
+		if found.Task.isOverdue(time.Now()) {
+			meta += " (overdue)"
+		}
+	}
+	if found.Task.Scheduled != nil {
+		meta += "\nScheduled: " + formatDate(found.Task.Scheduled)
+	}
+	if found.Task.CompletedAt != nil {
+		meta += "\nCompleted: " + found.Task.CompletedAt.Format("2006-01-02 15:04")
+	}
+	if found.Task.DeletedAt != nil {
+		meta += "\nDeleted: " + found.Task.DeletedAt.Format("2006-01-02 15:04")
+	}
+	if found.Task.Recur != "" {
+		meta += "\nRepeats: " + found.Task.Recur
+	}
+	if len(found.Task.DependsOn) > 0 {
+		meta += "\nDepends on: " + strings.Join(found.Task.DependsOn, ", ")
+		if open := openDependencies(found.Task, indexByID(tasks)); len(open) > 0 {
+			meta += "\nBlocked by: " + dependencyTitles(open)
+		}
+	}
+	if len(found.Task.TimeLog) > 0 {
+		meta += "\nTracked: " + formatDuration(found.Task.trackedTime(time.Now()))
+		if running := found.Task.runningEntry(); running != nil {
+			meta += " (running since " + running.Start.Format("15:04") + ")"
+		}
+	}
+	if len(found.Task.History) > 0 {
+		meta += "\nHistory:\n" + statusTimeline(found.Task.History)
+	}
+	fmt.Fprintln(w, header)
+	fmt.Fprintln(w, topicStyle.Render(meta))
+	if found.Task.Description != "" {
+		descStyle := lipgloss.NewStyle().Foreground(cliMuted)
+		fmt.Fprintln(w, descStyle.Render("\n"+found.Task.Description))
+	}
+	if !found.Task.hasGeneratedBody() {
+		fmt.Fprintln(w, "\n"+strings.TrimSpace(found.Task.Body))
+	}
+}
+
+// statusTimeline renders a status history one transition per line, with
+// how long the task spent in the status it left.
+func statusTimeline(history []StatusChange) string {
+	lines := make([]string, 0, len(history))
+	for i, change := range history {
+		line := "  " + change.At.Format("2006-01-02 15:04") + "  "
+		if change.From == "" {
+			line += "created as " + string(change.To)
+		} else {
+			line += fmt.Sprintf("%s → %s", change.From, change.To)
+			if i > 0 {
+				line += fmt.Sprintf(" (after %s)", formatDuration(change.At.Sub(history[i-1].At)))
+			}
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -3,24 +3,29 @@ package main
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
 
 // Stats command: show counts by status, topic, tag
-func NewStatsCmd(store *FileStore) *cobra.Command {
+func NewStatsCmd(store Storage) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "stats",
 		Short: "Show statistics about your tasks",
-		Long:  "Show statistics about your tasks: counts by status, topic, and tag.",
-		Run: func(cmd *cobra.Command, args []string) {
+		Long:  "Show statistics about your tasks, or those matching --search: counts by status, topic, and tag.",
+		RunE: func(cmd *cobra.Command, args []string) error {
 			tasks, err := store.LoadAllTasks()
 			if err != nil {
-				styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Error loading tasks: %v", err))
-				fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
-				return
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
 			}
+			search, _ := cmd.Flags().GetString("search")
+			q, err := parseQuery(search, time.Now())
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid query: %v", err))
+			}
+			tasks = q.filter(tasks)
 			statusCounts := make(map[string]int)
 			topicCounts := make(map[string]int)
 			tagCounts := make(map[string]int)
@@ -36,7 +41,17 @@ func NewStatsCmd(store *FileStore) *cobra.Command {
 			headStyle := lipgloss.NewStyle().Bold(true).Foreground(cliPrimary)
 			fmt.Fprintln(cmd.OutOrStdout(), headStyle.Render("Task Statistics"))
 			fmt.Fprintln(cmd.OutOrStdout(), "\nBy Status:")
-			for _, status := range []string{"todo", "in-progress", "done", "paused", "cancelled"} {
+			for _, d := range statuses {
+				fmt.Fprintf(cmd.OutOrStdout(), "  %s: %d\n", d.Name, statusCounts[string(d.Name)])
+				delete(statusCounts, string(d.Name))
+			}
+			// Statuses the workflow does not define, as left by hand edits
+			unknown := make([]string, 0, len(statusCounts))
+			for status := range statusCounts {
+				unknown = append(unknown, status)
+			}
+			sort.Strings(unknown)
+			for _, status := range unknown {
 				fmt.Fprintf(cmd.OutOrStdout(), "  %s: %d\n", status, statusCounts[status])
 			}
 			fmt.Fprintln(cmd.OutOrStdout(), "\nBy Topic:")
@@ -60,7 +75,9 @@ func NewStatsCmd(store *FileStore) *cobra.Command {
 			for _, tag := range tags {
 				fmt.Fprintf(cmd.OutOrStdout(), "  %s: %d\n", tag, tagCounts[tag])
 			}
+			return nil
 		},
 	}
+	cmd.Flags().StringP("search", "q", "", "Only count tasks matching this query, as in tada list --search")
 	return cmd
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// NewSubCmd manages a task's subtasks, stored as a Markdown checklist in
+// the task body.
+func NewSubCmd(store Storage) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sub",
+		Short: "Manage a task's subtasks",
+		Long:  "Add, check and uncheck subtasks. Subtasks are the Markdown checklist items (- [ ] ...) in a task's body.",
+	}
+
+	// loadTask resolves the task argument, printing an error on failure.
+	loadTask := func(cmd *cobra.Command, input string) (*TaskWithPath, error) {
+		tasks, err := store.LoadAllTasks()
+		if err != nil {
+			return nil, fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
+		}
+		found, err := resolveTask(cmd, store, tasks, input)
+		if err != nil {
+			return nil, fail(cmd, err, lookupErrorMessage(err))
+		}
+		return found, nil
+	}
+
+	save := func(cmd *cobra.Command, found *TaskWithPath, message string) error {
+		if err := store.UpdateTask(found); err != nil {
+			return fail(cmd, err, fmt.Sprintf("Failed to save: %v", err))
+		}
+		successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+		fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("%s (%s)", message, found.Task.progress())))
+		return nil
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "add [topic/]title|id text",
+		Short: "Add a subtask",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			found, err := loadTask(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			text := strings.Join(args[1:], " ")
+			found.Task.addChecklistItem(text)
+			return save(cmd, found, fmt.Sprintf("Subtask added: %s", text))
+		},
+	})
+
+	setDone := func(done bool) func(cmd *cobra.Command, args []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			found, err := loadTask(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			items := parseChecklist(found.Task.Body)
+			index, err := findChecklistItem(items, strings.Join(args[1:], " "))
+			if err == nil {
+				err = found.Task.setChecklistItem(index, done)
+			}
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Error: %v", err))
+			}
+			verb := "Checked"
+			if !done {
+				verb = "Unchecked"
+			}
+			return save(cmd, found, fmt.Sprintf("%s: %s", verb, items[index].text))
+		}
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "check [topic/]title|id number|text",
+		Short: "Mark a subtask done",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  setDone(true),
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "uncheck [topic/]title|id number|text",
+		Short: "Mark a subtask not done",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  setDone(false),
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list [topic/]title|id",
+		Short: "List a task's subtasks",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			found, err := loadTask(cmd, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			items := parseChecklist(found.Task.Body)
+			if len(items) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), lipgloss.NewStyle().Foreground(cliMuted).Render("No subtasks."))
+				return nil
+			}
+			for i, item := range items {
+				mark := "[ ]"
+				if item.done {
+					mark = "[x]"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s%d. %s %s\n", strings.Repeat(" ", item.depth), i+1, mark, item.text)
+			}
+			return nil
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// NewStartCmd starts the timer on a task.
+func NewStartCmd(store Storage) *cobra.Command {
+	return &cobra.Command{
+		Use:   "start [topic/]title|id",
+		Short: "Start tracking time on a task",
+		Long:  "Clock in on a task and set it to in-progress. Only one timer runs at a time; stop it with tada stop.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := strings.Join(args, " ")
+
+			tasks, err := store.LoadAllTasks()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
+			}
+			found, err := resolveTask(cmd, store, tasks, input)
+			if err != nil {
+				return fail(cmd, err, lookupErrorMessage(err))
+			}
+			now := time.Now()
+			err = journaled(store, fmt.Sprintf("start %q", found.Task.Title), func() error {
+				return startTaskTimer(store, found, now)
+			})
+			var running *timerRunningError
+			switch {
+			case errors.As(err, &running) && running.running.Task.ID == found.Task.ID:
+				return fail(cmd, err, fmt.Sprintf("Already tracking %q since %s.", found.Task.Title, running.running.Task.runningEntry().Start.Format("15:04")))
+			case errors.As(err, &running):
+				return fail(cmd, err, fmt.Sprintf("Error: %v. Stop it first with: tada stop", err))
+			case errors.Is(err, errInvalidTransition):
+				return fail(cmd, err, fmt.Sprintf("Error: %v", err))
+			case err != nil:
+				return fail(cmd, err, fmt.Sprintf("Failed to save: %v", err))
+			}
+			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Started %q at %s", found.Task.Title, now.Format("15:04"))))
+			return nil
+		},
+	}
+}
+
+// NewStopCmd stops the running timer.
+func NewStopCmd(store Storage) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the running timer",
+		Long:  "Clock out of the task whose timer is running. The task stays in-progress.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tasks, err := store.LoadAllTasks()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
+			}
+			running := runningTimer(tasks)
+			if running == nil {
+				mutedStyle := lipgloss.NewStyle().Foreground(cliMuted)
+				fmt.Fprintln(cmd.OutOrStdout(), mutedStyle.Render("No timer is running."))
+				return nil
+			}
+
+			now := time.Now()
+			elapsed, _ := running.Task.stopTimer(now)
+			err = journaled(store, fmt.Sprintf("stop %q", running.Task.Title), func() error {
+				return store.UpdateTask(running)
+			})
+			if err != nil {
+				return fail(cmd, err, fmt.Sprintf("Failed to save: %v", err))
+			}
+			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Stopped %q after %s (%s in total)", running.Task.Title, formatDuration(elapsed), formatDuration(running.Task.trackedTime(now)))))
+			return nil
+		},
+	}
+}
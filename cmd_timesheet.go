@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// timesheetRow is the time tracked for one group of a timesheet.
+type timesheetRow struct {
+	Group   string  `json:"group"`
+	Seconds int64   `json:"seconds"`
+	Hours   float64 `json:"hours"`
+	Tasks   int     `json:"tasks"`
+	total   time.Duration
+	seen    map[*Task]bool
+}
+
+// NewTimesheetCmd reports the time tracked with tada start and tada stop.
+func NewTimesheetCmd(store Storage) *cobra.Command {
+	var since, by, outputFormat string
+	cmd := &cobra.Command{
+		Use:   "timesheet",
+		Short: "Report tracked time",
+		Long:  "Sum the time tracked on active and archived tasks since a date, grouped by topic, tag or day. A running timer counts up to now.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now()
+			from, err := parseSince(since, now)
+			if err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --since: %v", err))
+			}
+			if by != "topic" && by != "tag" && by != "day" {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --by %q: use topic, tag or day", by))
+			}
+
+			tasks, err := store.LoadAllTasks()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading tasks: %v", err))
+			}
+			archived, err := store.LoadArchivedTasks()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading archived tasks: %v", err))
+			}
+			rows, total := timesheet([]map[string][]*TaskWithPath{tasks, archived}, by, from, now)
+
+			switch outputFormat {
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				enc.Encode(rows)
+				return nil
+			case "csv":
+				w := csv.NewWriter(cmd.OutOrStdout())
+				w.Write([]string{strings.ToUpper(by[:1]) + by[1:], "Seconds", "Hours", "Tasks"})
+				for _, r := range rows {
+					w.Write([]string{r.Group, fmt.Sprintf("%d", r.Seconds), fmt.Sprintf("%.2f", r.Hours), fmt.Sprintf("%d", r.Tasks)})
+				}
+				w.Flush()
+				return nil
+			}
+
+			if len(rows) == 0 {
+				mutedStyle := lipgloss.NewStyle().Foreground(cliMuted)
+				fmt.Fprintln(cmd.OutOrStdout(), mutedStyle.Render(fmt.Sprintf("No time tracked since %s.", from.Format("2006-01-02 15:04"))))
+				return nil
+			}
+			headStyle := lipgloss.NewStyle().Bold(true).Foreground(cliPrimary)
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, headStyle.Render(strings.ToUpper(by)+"\tTIME\tHOURS\tTASKS"))
+			for _, r := range rows {
+				fmt.Fprintf(w, "%s\t%s\t%.2f\t%d\n", r.Group, formatDuration(r.total), r.Hours, r.Tasks)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%.2f\t\n", headStyle.Render("Total"), formatDuration(total), roundHours(total))
+			w.Flush()
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "monday", "Start of the period (e.g. monday, yesterday, 7d, 2026-10-01)")
+	cmd.Flags().StringVar(&by, "by", "topic", "Group by: topic, tag or day")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format: csv, json, or table (default)")
+	return cmd
+}
+
+// timesheet groups the time tracked since from. By tag, a task counts
+// towards each of its tags; by day, intervals are split at midnight. Days
+// are listed in order, other groups by most time first. total counts
+// every interval once, whatever the grouping.
+func timesheet(trees []map[string][]*TaskWithPath, by string, from, now time.Time) (rows []*timesheetRow, total time.Duration) {
+	groups := make(map[string]*timesheetRow)
+	add := func(group string, task *Task, d time.Duration) {
+		r, ok := groups[group]
+		if !ok {
+			r = &timesheetRow{Group: group, seen: make(map[*Task]bool)}
+			groups[group] = r
+		}
+		r.total += d
+		if !r.seen[task] {
+			r.seen[task] = true
+			r.Tasks++
+		}
+	}
+
+	for _, tree := range trees {
+		for topic, list := range tree {
+			for _, t := range list {
+				for _, span := range t.Task.timeSpans(from, time.Time{}, now) {
+					total += span[1].Sub(span[0])
+					switch by {
+					case "day":
+						for start := span[0]; start.Before(span[1]); {
+							end := startOfDay(start).AddDate(0, 0, 1)
+							if end.After(span[1]) {
+								end = span[1]
+							}
+							add(start.Format(dateLayout), t.Task, end.Sub(start))
+							start = end
+						}
+					case "tag":
+						if len(t.Task.Tags) == 0 {
+							add("-", t.Task, span[1].Sub(span[0]))
+						}
+						for _, tag := range t.Task.Tags {
+							add(tag, t.Task, span[1].Sub(span[0]))
+						}
+					default:
+						group := topic
+						if group == "" {
+							group = "."
+						}
+						add(group, t.Task, span[1].Sub(span[0]))
+					}
+				}
+			}
+		}
+	}
+
+	rows = make([]*timesheetRow, 0, len(groups))
+	for _, r := range groups {
+		r.Seconds = int64(r.total / time.Second)
+		r.Hours = roundHours(r.total)
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if by != "day" && rows[i].total != rows[j].total {
+			return rows[i].total > rows[j].total
+		}
+		return rows[i].Group < rows[j].Group
+	})
+	return rows, total
+}
+
+// roundHours converts d to hours with two decimals.
+func roundHours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
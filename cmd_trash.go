@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// NewTrashCmd browses, restores and permanently removes deleted tasks.
+func NewTrashCmd(store Storage, cfg *Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Browse and restore deleted tasks",
+		Long:  "List, show and restore deleted tasks, or empty the trash for good.",
+	}
+
+	list := newTaskListCmd(cfg, func(*cobra.Command) (map[string][]*TaskWithPath, error) {
+		return store.LoadTrashedTasks()
+	})
+	list.Use = "list"
+	list.Short = "List deleted tasks"
+	list.Long = "List deleted tasks with the same filtering, searching, sorting and output options as tada list"
+
+	show := newTaskShowCmd(store, store.LoadTrashedTasks)
+	show.Use = "show [topic/]title|id"
+	show.Short = "Show details for a deleted task"
+
+	restore := &cobra.Command{
+		Use:   "restore [topic/]title|id",
+		Short: "Restore a deleted task",
+		Long:  "Move a deleted task back to where it was deleted from, with all its content.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := strings.Join(args, " ")
+
+			trashed, err := store.LoadTrashedTasks()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading trash: %v", err))
+			}
+			found, err := resolveTask(cmd, store, trashed, input)
+			if err != nil {
+				return fail(cmd, err, fmt.Sprintf("Error restoring task: %s", lookupErrorMessage(err)))
+			}
+			if err := store.RestoreTrashedTask(found); err != nil {
+				return fail(cmd, err, fmt.Sprintf("Error restoring task: %v", err))
+			}
+
+			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Task restored: %s", found.Task.Title)))
+			if found.Topic != "" {
+				topicStyle := lipgloss.NewStyle().Foreground(cliSecondary)
+				fmt.Fprintln(cmd.OutOrStdout(), topicStyle.Render(fmt.Sprintf("Topic: %s", found.Topic)))
+			}
+			return nil
+		},
+	}
+
+	var olderThan string
+	empty := &cobra.Command{
+		Use:   "empty",
+		Short: "Permanently remove deleted tasks",
+		Long:  "Permanently remove every task in the trash, or only those deleted longer ago than --older-than.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cutoff time.Time
+			if olderThan != "" {
+				var err error
+				if cutoff, err = parseAge(olderThan, time.Now()); err != nil {
+					return fail(cmd, errInvalidInput, fmt.Sprintf("Error: %v", err))
+				}
+			}
+
+			trashed, err := store.LoadTrashedTasks()
+			if err != nil {
+				return fail(cmd, errStorage, fmt.Sprintf("Error loading trash: %v", err))
+			}
+			var toPurge []*TaskWithPath
+			for _, taskList := range trashed {
+				for _, t := range taskList {
+					// Tasks without a deletion time are kept unless emptying everything
+					if olderThan != "" && (t.Task.DeletedAt == nil || !t.Task.DeletedAt.Before(cutoff)) {
+						continue
+					}
+					toPurge = append(toPurge, t)
+				}
+			}
+
+			// One journal operation, so a single undo brings back every
+			// purged task
+			purged := 0
+			var failed error
+			journaled(store, fmt.Sprintf("empty trash (%d tasks)", len(toPurge)), func() error {
+				for _, t := range toPurge {
+					if err := store.PurgeTask(t); err != nil {
+						failed = fail(cmd, err, fmt.Sprintf("Error removing %s: %v", t.Task.Title, err))
+						continue
+					}
+					purged++
+				}
+				return nil
+			})
+
+			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("Permanently removed %d tasks.", purged)))
+			return failed
+		},
+	}
+	empty.Flags().StringVar(&olderThan, "older-than", "", "Only remove tasks deleted longer ago than this (e.g. 30d, 2w, 6 months)")
+
+	cmd.AddCommand(list, show, restore, empty)
+	return cmd
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTrashCommands(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{Title: "Oops", Description: "Important notes", Tags: []string{"keep"}, Status: StatusInProgress, Priority: 1})
+	store.SaveTask("work", &Task{Title: "Old junk", Status: StatusTodo, Priority: 3})
+	tasks, _ := store.LoadAllTasks()
+	original := tasks["work"][0].FilePath
+	if tasks["work"][0].Task.Title != "Oops" {
+		original = tasks["work"][1].FilePath
+	}
+
+	if out := runWithStore(NewDeleteCmd(store), "work/Oops"); !strings.Contains(out, "tada trash restore") {
+		t.Errorf("Expected a restore hint, got: %s", out)
+	}
+	runWithStore(NewDeleteCmd(store), "work/Old junk")
+	if _, err := os.Stat(original); !os.IsNotExist(err) {
+		t.Errorf("Expected the task file to leave tasks/")
+	}
+
+	out := runWithStore(NewTrashCmd(store, nil), "list", "--simple")
+	if !strings.Contains(out, "Oops") || !strings.Contains(out, "Old junk") {
+		t.Errorf("Expected both deleted tasks in the trash, got: %s", out)
+	}
+	if out := runWithStore(NewTrashCmd(store, nil), "show", "work/Oops"); !strings.Contains(out, "Deleted: ") || !strings.Contains(out, "Important notes") {
+		t.Errorf("Expected trash show to print the deleted task, got: %s", out)
+	}
+
+	// Age one deletion so only it is emptied
+	trashed, _ := store.LoadTrashedTasks()
+	for _, task := range trashed["work"] {
+		if task.Task.Title == "Old junk" {
+			old := time.Now().AddDate(0, 0, -40)
+			task.Task.DeletedAt = &old
+			store.UpdateTask(task)
+		}
+	}
+	if out := runWithStore(NewTrashCmd(store, nil), "empty", "--older-than", "30d"); !strings.Contains(out, "Permanently removed 1 tasks") {
+		t.Errorf("Expected one task to be purged, got: %s", out)
+	}
+	if out := runWithStore(NewTrashCmd(store, nil), "empty", "--older-than", "soon"); !strings.Contains(out, "unrecognised age") {
+		t.Errorf("Expected an invalid age to be rejected, got: %s", out)
+	}
+
+	if out := runWithStore(NewTrashCmd(store, nil), "restore", "work/Oops"); !strings.Contains(out, "Task restored: Oops") {
+		t.Fatalf("Expected restore to succeed, got: %s", out)
+	}
+	tasks, _ = store.LoadAllTasks()
+	if len(tasks["work"]) != 1 {
+		t.Fatalf("Expected only the restored task to be active, got %d", len(tasks["work"]))
+	}
+	restored := tasks["work"][0]
+	if restored.FilePath != original || restored.Task.Description != "Important notes" || restored.Task.Status != StatusInProgress ||
+		len(restored.Task.Tags) != 1 || restored.Task.DeletedAt != nil || restored.Task.DeletedFrom != "" {
+		t.Errorf("Expected the task to come back unchanged at %s, got %+v at %s", original, restored.Task, restored.FilePath)
+	}
+	if trashed, _ := store.LoadTrashedTasks(); len(trashed["work"]) != 0 {
+		t.Errorf("Expected an empty trash, got %d tasks", len(trashed["work"]))
+	}
+
+	// Emptying the trash is undone as a whole
+	store.SaveTask("home", &Task{Title: "Spare", Status: StatusTodo})
+	runWithStore(NewDeleteCmd(store), "work/Oops")
+	runWithStore(NewDeleteCmd(store), "home/Spare")
+	if out := runWithStore(NewTrashCmd(store, nil), "empty"); !strings.Contains(out, "Permanently removed 2 tasks") {
+		t.Fatalf("Expected both tasks to be purged, got: %s", out)
+	}
+	if _, err := store.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if trashed, _ := store.LoadTrashedTasks(); len(trashed["work"]) != 1 || len(trashed["home"]) != 1 {
+		t.Errorf("Expected one undo to bring back both tasks, got %v", trashed)
+	}
+}
+
+func TestTUIUndoDeleteKeepsContent(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("", &Task{Title: "Precious", Description: "Do not lose me", Tags: []string{"a", "b"}, Status: StatusTodo, Priority: 2})
+	tasks, _ := store.LoadAllTasks()
+
+	m := model{tasks: tasks, store: store, expanded: map[string]bool{}}
+	m.buildItems()
+	for i, it := range m.items {
+		if it.task != nil {
+			m.selected = i
+		}
+	}
+	m2, _ := m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m3, _ := m2.(model).updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if trashed, _ := store.LoadTrashedTasks(); len(trashed[""]) != 1 {
+		t.Fatalf("Expected the task in the trash, got %d", len(trashed[""]))
+	}
+	m3.(model).updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+
+	tasks, _ = store.LoadAllTasks()
+	if len(tasks[""]) != 1 || tasks[""][0].Task.Description != "Do not lose me" || len(tasks[""][0].Task.Tags) != 2 {
+		t.Errorf("Expected undo to restore the full task, got %+v", tasks[""])
+	}
+}
@@ -4,19 +4,20 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func NewTuiCmd(store *FileStore) *cobra.Command {
+func NewTuiCmd(store Storage) *cobra.Command {
 	return &cobra.Command{
 		Use:   "tui",
 		Short: "Start the TUI interface",
 		Long:  "Start the interactive terminal user interface",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, _ := loadConfig()
 			tadaDir := ""
-			if store != nil {
-				tadaDir = store.basePath
+			if fs, ok := store.(*FileStore); ok {
+				tadaDir = fs.basePath
 			}
 			showWelcomeIfNeeded(cfg, tadaDir)
-			RunTUI()
+			RunTUIWithConfig(store, cfg)
+			return nil
 		},
 	}
 }
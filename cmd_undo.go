@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// NewUndoCmd reverts the most recent change recorded in the journal.
+func NewUndoCmd(store Storage) *cobra.Command {
+	return newJournalStepCmd(store, "undo", "Undo the last change",
+		"Revert the most recent change made by any tada command or the TUI, even after it exited. Repeat to go further back.",
+		"Undone", func(j journal) (*journalEntry, error) { return j.Undo() })
+}
+
+// NewRedoCmd reapplies the most recently undone change.
+func NewRedoCmd(store Storage) *cobra.Command {
+	return newJournalStepCmd(store, "redo", "Redo the last undone change",
+		"Reapply the most recently undone change. Making any other change discards what can be redone.",
+		"Redone", func(j journal) (*journalEntry, error) { return j.Redo() })
+}
+
+// newJournalStepCmd builds the undo and redo commands, which differ only in
+// the journal step they take.
+func newJournalStepCmd(store Storage, use, short, long, verb string, step func(journal) (*journalEntry, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			j, ok := store.(journal)
+			if !ok {
+				return fail(cmd, errors.ErrUnsupported, fmt.Sprintf("Error: %s is not supported by this store", use))
+			}
+			entry, err := step(j)
+			if err != nil {
+				msg := fmt.Sprintf("Error: %v", err)
+				switch {
+				case errors.Is(err, errNothingToUndo), errors.Is(err, errNothingToRedo):
+					msg = fmt.Sprintf("Nothing to %s.", use)
+				case errors.Is(err, errConflict):
+					msg = fmt.Sprintf("Cannot %s %s: %v. Check tada log and the file, then edit it by hand.", use, entry.Summary, err)
+				}
+				return fail(cmd, err, msg)
+			}
+			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("%s: %s", verb, entry.Summary)))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// View is a saved query and sort order for tada list --view, tada view and
+// the TUI.
+type View struct {
+	Query string `yaml:"query,omitempty"`
+	Sort  string `yaml:"sort,omitempty"`
+}
+
+// viewSubcommands cannot be view names, since tada view <name> runs a view.
+var viewSubcommands = map[string]bool{"save": true, "list": true, "delete": true, "help": true}
+
+// view returns the saved view called name.
+func (c *Config) view(name string) (View, bool) {
+	if c == nil {
+		return View{}, false
+	}
+	v, ok := c.Views[name]
+	return v, ok
+}
+
+// viewNames returns the names of the saved views, sorted.
+func (c *Config) viewNames() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.Views))
+	for name := range c.Views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewViewCmd lists tasks through a saved view, and saves, lists and deletes
+// views. tada view <name> is tada list --view <name>, with the same flags.
+func NewViewCmd(store Storage, cfg *Config) *cobra.Command {
+	cmd := NewListCmd(store, cfg)
+	cmd.Use = "view [name]"
+	cmd.Short = "List tasks through a saved view"
+	cmd.Long = "List the tasks of a view saved with tada view save, the same as tada list --view. Other tada list flags add to the view. Without a name, the saved views are listed."
+	cmd.Args = cobra.MaximumNArgs(1)
+	runList := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			printViews(cmd, cfg)
+			return nil
+		}
+		cmd.Flags().Set("view", args[0])
+		return runList(cmd, nil)
+	}
+
+	save := &cobra.Command{
+		Use:   "save name [query...]",
+		Short: "Save a query and sort order as a view",
+		Long:  "Save a query, as taken by tada list --search, and a sort order under a name, in the local config or with --global in the global one. Saving an existing name replaces the view. Quote a query with a negated term, or put it after --, so -tag:someday is not read as a flag.",
+		Example: `  tada view save urgent 'tag:urgent priority<=2' --sort priority
+  tada view save active --sort due -- is:open -tag:someday`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, query := args[0], strings.Join(args[1:], " ")
+			sortBy, _ := cmd.Flags().GetString("sort")
+			global, _ := cmd.Flags().GetBool("global")
+
+			switch {
+			case !fieldNamePattern.MatchString(name):
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid view name %q: use lowercase letters, digits, - and _.", name))
+			case viewSubcommands[name]:
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid view name %q: it is a tada view subcommand.", name))
+			}
+			if _, isField := fieldDef(sortBy); sortBy != "" && !builtinSortKeys[sortBy] && !isField {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid --sort %q (use created, priority, title, status, due, or a custom field).", sortBy))
+			}
+			if _, err := parseQuery(query, time.Now()); err != nil {
+				return fail(cmd, errInvalidInput, fmt.Sprintf("Invalid query: %v", err))
+			}
+
+			file, err := loadConfigFile(global)
+			if err != nil {
+				return fail(cmd, err, fmt.Sprintf("Error loading config: %v", err))
+			}
+			if file.Views == nil {
+				file.Views = make(map[string]View)
+			}
+			file.Views[name] = View{Query: query, Sort: sortBy}
+			if err := saveConfigKey(global, "views", file.Views); err != nil {
+				return fail(cmd, err, fmt.Sprintf("Failed to save config: %v", err))
+			}
+
+			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("View saved: %s", name)))
+			hintStyle := lipgloss.NewStyle().Foreground(cliMuted)
+			fmt.Fprintln(cmd.OutOrStdout(), hintStyle.Render(fmt.Sprintf("Show it with: tada view %s", name)))
+			return nil
+		},
+	}
+	save.Flags().String("sort", "", "Sort by: created, priority, title, status, due, or a custom field")
+	save.Flags().Bool("global", false, "Save in the global config instead of the local one")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List saved views",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printViews(cmd, cfg)
+			return nil
+		},
+	}
+
+	del := &cobra.Command{
+		Use:   "delete name",
+		Short: "Delete a saved view",
+		Long:  "Delete a view from the local config, or with --global from the global one.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			global, _ := cmd.Flags().GetBool("global")
+			file, err := loadConfigFile(global)
+			if err != nil {
+				return fail(cmd, err, fmt.Sprintf("Error loading config: %v", err))
+			}
+			if _, ok := file.Views[name]; !ok {
+				scope := "local"
+				if global {
+					scope = "global"
+				}
+				return fail(cmd, errInvalidInput, fmt.Sprintf("No view %q in the %s config.", name, scope))
+			}
+			delete(file.Views, name)
+			var views interface{}
+			if len(file.Views) > 0 {
+				views = file.Views
+			}
+			if err := saveConfigKey(global, "views", views); err != nil {
+				return fail(cmd, err, fmt.Sprintf("Failed to save config: %v", err))
+			}
+			successStyle := lipgloss.NewStyle().Foreground(cliPrimary).Bold(true)
+			fmt.Fprintln(cmd.OutOrStdout(), successStyle.Render(fmt.Sprintf("View deleted: %s", name)))
+			return nil
+		},
+	}
+	del.Flags().Bool("global", false, "Delete from the global config instead of the local one")
+
+	cmd.AddCommand(save, list, del)
+	return cmd
+}
+
+// printViews lists the saved views with their query and sort order.
+func printViews(cmd *cobra.Command, cfg *Config) {
+	names := cfg.viewNames()
+	if len(names) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), lipgloss.NewStyle().Foreground(cliMuted).Render("No saved views. Save one with: tada view save <name> <query>"))
+		return
+	}
+	headStyle := lipgloss.NewStyle().Bold(true).Foreground(cliPrimary)
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, headStyle.Render("NAME\tQUERY\tSORT"))
+	for _, name := range names {
+		view := cfg.Views[name]
+		query, sortBy := view.Query, view.Sort
+		if query == "" {
+			query = "-"
+		}
+		if sortBy == "" {
+			sortBy = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, query, sortBy)
+	}
+	w.Flush()
+}
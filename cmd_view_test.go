@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSavedViews(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+	t.Chdir(dir)
+	os.Mkdir(".tada", 0755)
+	globalConfig := filepath.Join(dir, "xdg", "tada", "config.yaml")
+	os.MkdirAll(filepath.Dir(globalConfig), 0755)
+	os.WriteFile(globalConfig, []byte("# mine\ndefault_sort: priority\ntags: [a, b]\n"), 0644)
+
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{ID: "aaaa1111", Title: "Deploy api", Status: StatusTodo, Priority: 2, Tags: []string{"urgent"}})
+	store.SaveTask("work", &Task{ID: "bbbb2222", Title: "Fix login", Status: StatusTodo, Priority: 1, Tags: []string{"urgent"}})
+	store.SaveTask("home", &Task{ID: "cccc3333", Title: "Water plants", Status: StatusTodo, Priority: 1})
+
+	if out := runWithStore(NewViewCmd(store, nil), "save", "urgent", "tag:urgent", "priority<=2", "--sort", "priority"); !strings.Contains(out, "View saved: urgent") {
+		t.Fatalf("Expected the view to be saved, got: %s", out)
+	}
+	runWithStore(NewViewCmd(store, nil), "save", "home", "topic:home", "--global")
+	if out := runWithStore(NewViewCmd(store, nil), "save", "calm", "--", "tag:urgent", "-tag:work"); !strings.Contains(out, "View saved: calm") {
+		t.Fatalf("Expected a negated term after -- to be saved, got: %s", out)
+	}
+	for _, args := range [][]string{
+		{"save", "bad", "stauts:todo"},
+		{"save", "list", "tag:urgent"},
+		{"save", "bad", "--sort", "size"},
+	} {
+		if out := runWithStore(NewViewCmd(store, nil), args...); !strings.Contains(out, "Invalid") {
+			t.Errorf("tada view %s: expected it to be rejected, got: %s", strings.Join(args, " "), out)
+		}
+	}
+
+	// Views from the local and the global config are merged
+	cfg, _ := loadConfig()
+	if names := strings.Join(cfg.viewNames(), ","); names != "calm,home,urgent" {
+		t.Fatalf("Expected all views, got %q", names)
+	}
+	// Saving writes only the views, so the global settings still apply
+	if cfg.DefaultSort != "priority" || len(cfg.Tags) != 2 {
+		t.Errorf("Expected the global settings to survive, got %q and %v", cfg.DefaultSort, cfg.Tags)
+	}
+	if data, _ := os.ReadFile(globalConfig); !strings.Contains(string(data), "# mine") {
+		t.Errorf("Expected the global config to keep its comment, got: %s", data)
+	}
+	if out := runWithStore(NewViewCmd(store, cfg), "urgent", "--ids"); out != "bbbb2222\naaaa1111\n" {
+		t.Errorf("Expected the view's query and sort, got: %q", out)
+	}
+	if out := runWithStore(NewListCmd(store, cfg), "--view", "urgent", "--sort", "title", "--ids"); out != "aaaa1111\nbbbb2222\n" {
+		t.Errorf("Expected --sort to override the view's, got: %q", out)
+	}
+	if out := runWithStore(NewListCmd(store, cfg), "--view", "urgent", "-q", "login", "--ids"); out != "bbbb2222\n" {
+		t.Errorf("Expected --search to add to the view's query, got: %q", out)
+	}
+	if out := runWithStore(NewListCmd(store, cfg), "--view", "nope"); !strings.Contains(out, `Unknown view "nope"`) {
+		t.Errorf("Expected an unknown view to be rejected, got: %s", out)
+	}
+	if out := runWithStore(NewViewCmd(store, cfg), "list"); !strings.Contains(out, "tag:urgent priority<=2") || !strings.Contains(out, "topic:home") {
+		t.Errorf("Expected the saved views to be listed, got: %s", out)
+	}
+
+	// The TUI switches views with tab
+	m := initialModel()
+	m.applyConfig(cfg)
+	m.tasks, _ = store.LoadAllTasks()
+	m.buildItems()
+	for _, want := range []string{"calm", "home", "urgent", ""} {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		m = next.(model)
+		if m.view != want {
+			t.Fatalf("Expected tab to switch to view %q, got %q", want, m.view)
+		}
+		if want == "urgent" {
+			out := m.viewList()
+			if !strings.Contains(out, "Views: All • calm • home • urgent") || strings.Contains(out, "Water plants") || strings.Index(out, "Fix login") > strings.Index(out, "Deploy api") {
+				t.Errorf("Expected the urgent view, sorted by priority, got:\n%s", out)
+			}
+		}
+	}
+
+	// Deleting touches only the chosen config
+	if out := runWithStore(NewViewCmd(store, cfg), "delete", "home"); !strings.Contains(out, `No view "home" in the local config`) {
+		t.Errorf("Expected the global view not to be found locally, got: %s", out)
+	}
+	runWithStore(NewViewCmd(store, cfg), "delete", "urgent")
+	runWithStore(NewViewCmd(store, cfg), "delete", "calm")
+	local, _ := loadConfigFile(false)
+	global, _ := loadConfigFile(true)
+	if len(local.Views) != 0 || len(global.Views) != 1 {
+		t.Errorf("Expected only the local view to be deleted, got %v and %v", local.Views, global.Views)
+	}
+}
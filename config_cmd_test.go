@@ -37,3 +37,36 @@ func TestConfigCmd_Set(t *testing.T) {
 		t.Errorf("Expected config updated message, got: %s", out.String())
 	}
 }
+
+func TestConfigCmd_SetWritesOnlyTheKey(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	saveConfigKey(true, "default_sort", "priority")
+	run := func(args ...string) string {
+		cmd := NewConfigCmd()
+		cmd.SetArgs(append([]string{"set"}, args...))
+		var out strings.Builder
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.Execute()
+		return out.String()
+	}
+
+	if out := run("theme", "light"); !strings.Contains(out, "Config updated") {
+		t.Fatalf("Expected config updated message, got: %s", out)
+	}
+	local, _ := loadConfigFile(false)
+	if local.Theme != "light" || local.DefaultSort != "" {
+		t.Errorf("Expected only theme in the local config, got %+v", local)
+	}
+
+	if out := run("default_status", "shipped"); !strings.Contains(out, "Invalid default_status") {
+		t.Errorf("Expected an unknown status to be rejected, got: %s", out)
+	}
+	run("default_status", "in-progress")
+	run("show_welcome", "false")
+	local, _ = loadConfigFile(false)
+	if local.DefaultStatus != "in-progress" || local.ShowWelcome == nil || *local.ShowWelcome {
+		t.Errorf("Expected the status and show_welcome to be saved, got %+v", local)
+	}
+}
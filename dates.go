@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout is how due and scheduled dates are displayed.
+const dateLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// startOfDay returns midnight of t's day in t's location.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// parseDate parses a date relative to now. It accepts ISO dates
+// (2026-11-01, 2026-11-01 14:30, RFC 3339), "today", "tomorrow",
+// "yesterday", weekday names ("fri", "next friday" — the coming one),
+// "next week", "next month", "in 3 days", "in 2 weeks" and the shorthands
+// "+3d" and "+2w". Day-based inputs resolve to midnight local time.
+func parseDate(input string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	today := startOfDay(now)
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", dateLayout} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	switch s {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "next week":
+		return today.AddDate(0, 0, 7), nil
+	case "next month":
+		return today.AddDate(0, 1, 0), nil
+	}
+
+	if day, ok := weekdays[strings.TrimPrefix(s, "next ")]; ok {
+		ahead := (int(day) - int(today.Weekday()) + 7) % 7
+		if ahead == 0 {
+			ahead = 7
+		}
+		return today.AddDate(0, 0, ahead), nil
+	}
+
+	if n, unit, ok := parseOffset(s); ok {
+		switch unit {
+		case "d", "day", "days":
+			return today.AddDate(0, 0, n), nil
+		case "w", "week", "weeks":
+			return today.AddDate(0, 0, 7*n), nil
+		case "month", "months":
+			return today.AddDate(0, n, 0), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognised date %q (try 2026-11-01, tomorrow, next fri or in 3 days)", input)
+}
+
+// parseOffset splits "in 3 days", "+3d" or "3d" into a count and a unit.
+func parseOffset(s string) (int, string, bool) {
+	s = strings.TrimPrefix(s, "in ")
+	s = strings.TrimPrefix(s, "+")
+	fields := strings.Fields(s)
+	if len(fields) == 2 {
+		n, err := strconv.Atoi(fields[0])
+		return n, fields[1], err == nil
+	}
+	if len(fields) == 1 {
+		i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return 0, "", false
+		}
+		n, err := strconv.Atoi(s[:i])
+		return n, s[i:], err == nil
+	}
+	return 0, "", false
+}
+
+// parseAge parses a span such as "30d", "2w", "6 months" or "12h" and
+// returns the moment that long before now.
+func parseAge(input string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	if n, unit, ok := parseOffset(s); ok && n >= 0 {
+		switch unit {
+		case "h", "hour", "hours":
+			return now.Add(-time.Duration(n) * time.Hour), nil
+		case "d", "day", "days":
+			return now.AddDate(0, 0, -n), nil
+		case "w", "week", "weeks":
+			return now.AddDate(0, 0, -7*n), nil
+		case "month", "months":
+			return now.AddDate(0, -n, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised age %q (try 30d, 2w, 6 months or 12h)", input)
+}
+
+// parseDateFlag parses an optional date flag value. An empty value yields
+// nil; "none" clears the date and is reported through clear.
+func parseDateFlag(value string, now time.Time) (date *time.Time, clear bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return nil, false, nil
+	case "none":
+		return nil, true, nil
+	}
+	t, err := parseDate(value, now)
+	if err != nil {
+		return nil, false, err
+	}
+	return &t, false, nil
+}
+
+// formatDuration renders a duration in its two largest units, such as
+// "3d 4h", "2h 5m" or "12m".
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	minutes := int(d/time.Minute) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// formatDate renders an optional date for display, or "-" when unset.
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	if t.Equal(startOfDay(*t)) {
+		return t.Format(dateLayout)
+	}
+	return t.Format("2006-01-02 15:04")
+}
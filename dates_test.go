@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	// Thursday, 15 October 2026
+	now := time.Date(2026, 10, 15, 9, 30, 0, 0, time.Local)
+
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{"2026-11-01", "2026-11-01 00:00"},
+		{"2026-11-01 14:30", "2026-11-01 14:30"},
+		{"today", "2026-10-15 00:00"},
+		{"Tomorrow", "2026-10-16 00:00"},
+		{"yesterday", "2026-10-14 00:00"},
+		{"fri", "2026-10-16 00:00"},
+		{"next fri", "2026-10-16 00:00"},
+		{"wednesday", "2026-10-21 00:00"},
+		{"thu", "2026-10-22 00:00"},
+		{"next week", "2026-10-22 00:00"},
+		{"next month", "2026-11-15 00:00"},
+		{"in 3 days", "2026-10-18 00:00"},
+		{"+2w", "2026-10-29 00:00"},
+		{"10d", "2026-10-25 00:00"},
+		{"in 1 month", "2026-11-15 00:00"},
+	}
+	for _, tc := range testCases {
+		got, err := parseDate(tc.input, now)
+		if err != nil {
+			t.Errorf("parseDate(%q) returned error: %v", tc.input, err)
+			continue
+		}
+		if got.Format("2006-01-02 15:04") != tc.want {
+			t.Errorf("parseDate(%q) = %s, want %s", tc.input, got.Format("2006-01-02 15:04"), tc.want)
+		}
+	}
+
+	for _, bad := range []string{"someday", "3", "in x days", "next blursday"} {
+		if _, err := parseDate(bad, now); err == nil {
+			t.Errorf("Expected parseDate(%q) to fail", bad)
+		}
+	}
+}
+
+func TestTaskIsOverdue(t *testing.T) {
+	now := time.Date(2026, 10, 15, 9, 30, 0, 0, time.Local)
+	yesterday := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	today := time.Date(2026, 10, 15, 0, 0, 0, 0, time.Local)
+	earlierToday := time.Date(2026, 10, 15, 8, 0, 0, 0, time.Local)
+
+	if !(&Task{Status: StatusTodo, Due: &yesterday}).isOverdue(now) {
+		t.Errorf("Expected task due yesterday to be overdue")
+	}
+	if (&Task{Status: StatusTodo, Due: &today}).isOverdue(now) {
+		t.Errorf("Expected task due today not to be overdue")
+	}
+	if !(&Task{Status: StatusTodo, Due: &earlierToday}).isOverdue(now) {
+		t.Errorf("Expected task due earlier today at a set time to be overdue")
+	}
+	if (&Task{Status: StatusDone, Due: &yesterday}).isOverdue(now) {
+		t.Errorf("Expected closed task never to be overdue")
+	}
+}
+
+func TestListCmd_DueFilters(t *testing.T) {
+	store := NewMemoryStorage()
+	runWithStore(NewAddCmd(store), "Past", "--due", "2000-01-01")
+	runWithStore(NewAddCmd(store), "Soon", "--due", "tomorrow")
+	runWithStore(NewAddCmd(store), "Later", "--due", "in 30 days", "--scheduled", "in 20 days")
+	runWithStore(NewAddCmd(store), "Whenever")
+
+	out := runWithStore(NewListCmd(store, nil), "--overdue", "--simple")
+	if !strings.Contains(out, "Past") || strings.Contains(out, "Soon") || strings.Contains(out, "Whenever") {
+		t.Errorf("Expected only the overdue task, got: %s", out)
+	}
+
+	out = runWithStore(NewListCmd(store, nil), "--due-after", "today", "--due-before", "in 7 days", "--simple")
+	if !strings.Contains(out, "Soon") || strings.Contains(out, "Later") || strings.Contains(out, "Past") {
+		t.Errorf("Expected only the task due this week, got: %s", out)
+	}
+
+	tasks, _ := store.LoadAllTasks()
+	list := tasks[""]
+	sortTasks(list, "due")
+	var order []string
+	for _, task := range list {
+		order = append(order, task.Task.Title)
+	}
+	if strings.Join(order, ",") != "Past,Soon,Later,Whenever" {
+		t.Errorf("Expected due sort with undated tasks last, got %v", order)
+	}
+
+	if out := runWithStore(NewEditCmd(store), "Soon", "--due", "none"); !strings.Contains(out, "Task updated") {
+		t.Fatalf("Expected edit to succeed, got: %s", out)
+	}
+	if out := runWithStore(NewAddCmd(store), "Bad", "--due", "someday"); !strings.Contains(out, "Invalid --due") {
+		t.Errorf("Expected invalid date error, got: %s", out)
+	}
+	for _, task := range list {
+		if task.Task.Title == "Soon" && task.Task.Due != nil {
+			t.Errorf("Expected --due none to clear the due date")
+		}
+	}
+}
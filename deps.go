@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// indexByID maps task IDs to the loaded tasks.
+func indexByID(tasks map[string][]*TaskWithPath) map[string]*TaskWithPath {
+	byID := make(map[string]*TaskWithPath)
+	for _, list := range tasks {
+		for _, t := range list {
+			if t.Task.ID != "" {
+				byID[t.Task.ID] = t
+			}
+		}
+	}
+	return byID
+}
+
+// openDependencies returns the dependencies of task that are still open.
+// Dependencies that were archived or no longer exist do not block.
+func openDependencies(task *Task, byID map[string]*TaskWithPath) []*TaskWithPath {
+	var open []*TaskWithPath
+	for _, id := range task.DependsOn {
+		if dep, ok := byID[id]; ok && !dep.Task.Status.isClosed() {
+			open = append(open, dep)
+		}
+	}
+	return open
+}
+
+// isReady reports whether a task can be worked on now: it is open and
+// nothing it depends on is still open.
+func isReady(task *Task, byID map[string]*TaskWithPath) bool {
+	if task.Status.isClosed() {
+		return false
+	}
+	return len(openDependencies(task, byID)) == 0
+}
+
+// resolveDependencies turns task references (IDs, ID prefixes or
+// topic/title) into task IDs.
+func resolveDependencies(tasks map[string][]*TaskWithPath, refs []string) ([]string, error) {
+	var ids []string
+	for _, ref := range refs {
+		dep, err := findTask(tasks, strings.TrimSpace(ref))
+		if err != nil {
+			return nil, fmt.Errorf("dependency %q: %s", ref, lookupErrorMessage(err))
+		}
+		if !containsString(ids, dep.Task.ID) {
+			ids = append(ids, dep.Task.ID)
+		}
+	}
+	return ids, nil
+}
+
+// checkDependencyCycle reports an error if giving task id the dependencies
+// deps would make it depend on itself, directly or transitively.
+func checkDependencyCycle(byID map[string]*TaskWithPath, id string, deps []string) error {
+	visited := make(map[string]bool)
+	var walk func(current string, path []string) []string
+	walk = func(current string, path []string) []string {
+		path = append(path, current)
+		if current == id {
+			return path
+		}
+		if visited[current] {
+			return nil
+		}
+		visited[current] = true
+		if t, ok := byID[current]; ok {
+			for _, next := range t.Task.DependsOn {
+				if cycle := walk(next, path); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		return nil
+	}
+	for _, dep := range deps {
+		if cycle := walk(dep, []string{id}); cycle != nil {
+			return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	return nil
+}
+
+// dependencyTitles renders tasks as a comma-separated list of titles.
+func dependencyTitles(deps []*TaskWithPath) string {
+	titles := make([]string, len(deps))
+	for i, d := range deps {
+		titles[i] = d.Task.Title
+	}
+	return strings.Join(titles, ", ")
+}
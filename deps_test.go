@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckDependencyCycle(t *testing.T) {
+	tasks := map[string][]*TaskWithPath{
+		"": {
+			{Task: &Task{ID: "aaaa0001", Title: "A", DependsOn: []string{"aaaa0002"}}},
+			{Task: &Task{ID: "aaaa0002", Title: "B", DependsOn: []string{"aaaa0003"}}},
+			{Task: &Task{ID: "aaaa0003", Title: "C"}},
+		},
+	}
+	byID := indexByID(tasks)
+
+	if err := checkDependencyCycle(byID, "aaaa0003", []string{"aaaa0001"}); err == nil ||
+		!strings.Contains(err.Error(), "aaaa0003 -> aaaa0001 -> aaaa0002 -> aaaa0003") {
+		t.Errorf("Expected a transitive cycle error, got %v", err)
+	}
+	if err := checkDependencyCycle(byID, "aaaa0003", []string{"aaaa0003"}); err == nil {
+		t.Error("Expected a task depending on itself to be rejected")
+	}
+	if err := checkDependencyCycle(byID, "aaaa0001", []string{"aaaa0003"}); err != nil {
+		t.Errorf("Expected no cycle, got %v", err)
+	}
+}
+
+func TestDependenciesCommands(t *testing.T) {
+	store := NewMemoryStorage()
+	runWithStore(NewAddCmd(store), "work/Design")
+	if out := runWithStore(NewAddCmd(store), "work/Build", "--depends-on", "work/Design"); !strings.Contains(out, "Task added") {
+		t.Fatalf("Expected add with dependency to succeed, got: %s", out)
+	}
+	design, build := store.tasks[0].Task, store.tasks[1].Task
+	if len(build.DependsOn) != 1 || build.DependsOn[0] != design.ID {
+		t.Fatalf("Expected Build to depend on Design's ID, got %v", build.DependsOn)
+	}
+
+	if out := runWithStore(NewEditCmd(store), "work/Design", "--depends-on", build.ID); !strings.Contains(out, "dependency cycle") {
+		t.Errorf("Expected edit to reject a cycle, got: %s", out)
+	}
+	if len(design.DependsOn) != 0 {
+		t.Errorf("Expected rejected dependency not to be stored, got %v", design.DependsOn)
+	}
+
+	out := runWithStore(NewListCmd(store, nil), "--ready")
+	if !strings.Contains(out, "Design") || strings.Contains(out, "Build") {
+		t.Errorf("Expected only Design to be ready, got: %s", out)
+	}
+	if out := runWithStore(NewListCmd(store, nil)); !strings.Contains(out, "todo (blocked)") {
+		t.Errorf("Expected Build to be marked blocked, got: %s", out)
+	}
+
+	if out := runWithStore(NewCompleteCmd(store), "work/Build"); !strings.Contains(out, "Warning: completing a task with open dependencies: Design") {
+		t.Errorf("Expected an open dependency warning, got: %s", out)
+	}
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	fstore "io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of problems reported by tada doctor.
+const (
+	problemMalformed      = "malformed"
+	problemInvalidStatus  = "invalid-status"
+	problemMissingID      = "missing-id"
+	problemDuplicateID    = "duplicate-id"
+	problemDuplicateTitle = "duplicate-title"
+	problemClosedActive   = "closed-not-archived"
+	problemFileName       = "filename-drift"
+	problemEmptyTopic     = "empty-topic"
+	problemTempFile       = "temp-file"
+)
+
+// errProblemsRemain is the cause of a doctor run that leaves problems
+// unfixed.
+var errProblemsRemain = errors.New("problems remain")
+
+// problem is one inconsistency found in the .tada directory.
+type problem struct {
+	Path    string `json:"path"`
+	Kind    string `json:"kind"`
+	Reason  string `json:"reason"`
+	Fixable bool   `json:"fixable"`
+	Fixed   bool   `json:"fixed,omitempty"`
+	Error   string `json:"error,omitempty"`
+	// fix applies the safe repair, if there is one.
+	fix func() error
+}
+
+// doctorReport is the result of checking a .tada directory.
+type doctorReport struct {
+	Checked  int        `json:"checked"`
+	Problems []*problem `json:"problems"`
+}
+
+// fileNamePrefix matches the timestamp generateFileName puts before the
+// slug, and fileNameCounter the suffix newFilePath adds after it.
+var (
+	fileNamePrefix  = regexp.MustCompile(`^\d{8}-\d{6}-`)
+	fileNameCounter = regexp.MustCompile(`-\d+\.md$`)
+)
+
+// Diagnose reads every file under tasks/, archive/ and trash/, bypassing
+// the index, and reports everything that is malformed or inconsistent.
+func (fs *FileStore) Diagnose() (*doctorReport, error) {
+	report := &doctorReport{}
+	active := make(map[string][]*TaskWithPath)
+	var all []*TaskWithPath
+
+	for _, dir := range []string{TasksDir, ArchiveDir, TrashDir} {
+		root := filepath.Join(fs.basePath, dir)
+		err := filepath.WalkDir(root, func(path string, d fstore.DirEntry, err error) error {
+			if err != nil {
+				if path == root && os.IsNotExist(err) {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() {
+				if path != root {
+					fs.checkEmptyTopic(report, path)
+				}
+				return nil
+			}
+			if strings.Contains(d.Name(), tempMarker) {
+				report.add(&problem{Path: path, Kind: problemTempFile, Reason: "leftover temporary file from an interrupted write", fix: fs.removeTempFile(path)})
+				return nil
+			}
+			if !strings.HasSuffix(path, ".md") {
+				return nil
+			}
+
+			report.Checked++
+			content, err := os.ReadFile(path)
+			var task *Task
+			if err == nil {
+				task, err = parseTask(content)
+			}
+			if err != nil {
+				report.add(&problem{Path: path, Kind: problemMalformed, Reason: err.Error()})
+				return nil
+			}
+			relPath, _ := filepath.Rel(root, path)
+			topic := filepath.Dir(relPath)
+			if topic == "." {
+				topic = ""
+			}
+			t := &TaskWithPath{Task: task, FilePath: path, Topic: topic, Hash: contentHash(content)}
+			all = append(all, t)
+			fs.checkTask(report, t, dir == TasksDir)
+			if dir == TasksDir {
+				active[topic] = append(active[topic], t)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fs.checkDuplicateIDs(report, all)
+	fs.checkDuplicateTitles(report, active)
+
+	sort.SliceStable(report.Problems, func(i, j int) bool {
+		return report.Problems[i].Path < report.Problems[j].Path
+	})
+	return report, nil
+}
+
+// Repair applies the safe fix of every fixable problem, recording the
+// outcome on each.
+func (r *doctorReport) Repair() {
+	for _, p := range r.Problems {
+		if p.fix == nil {
+			continue
+		}
+		if err := p.fix(); err != nil {
+			p.Error = err.Error()
+			continue
+		}
+		p.Fixed = true
+	}
+}
+
+// remaining counts the problems that are not fixed.
+func (r *doctorReport) remaining() int {
+	n := 0
+	for _, p := range r.Problems {
+		if !p.Fixed {
+			n++
+		}
+	}
+	return n
+}
+
+// displayPath shows path relative to the directory holding .tada, as
+// doctor reports it.
+func (fs *FileStore) displayPath(path string) string {
+	if rel, err := filepath.Rel(filepath.Dir(fs.basePath), path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func (r *doctorReport) add(p *problem) {
+	p.Fixable = p.fix != nil
+	r.Problems = append(r.Problems, p)
+}
+
+// checkTask reports problems with a single parsed task.
+func (fs *FileStore) checkTask(report *doctorReport, t *TaskWithPath, active bool) {
+	if !t.Task.Status.isValid() {
+		p := &problem{Path: t.FilePath, Kind: problemInvalidStatus, Reason: fmt.Sprintf("unknown status %q", t.Task.Status)}
+		if status, ok := normalizeStatus(string(t.Task.Status)); ok {
+			p.Reason += fmt.Sprintf(", will be set to %s", status)
+			p.fix = func() error {
+				t.Task.setStatus(status)
+				return fs.UpdateTask(t)
+			}
+		}
+		report.add(p)
+	}
+
+	if t.Task.ID == "" {
+		report.add(&problem{Path: t.FilePath, Kind: problemMissingID, Reason: "task has no ID", fix: func() error {
+			return fs.backfillIDs(map[string][]*TaskWithPath{t.Topic: {t}})
+		}})
+	}
+
+	if active && t.Task.Status.isClosed() {
+		report.add(&problem{Path: t.FilePath, Kind: problemClosedActive, Reason: fmt.Sprintf("task is %s but was not archived", t.Task.Status), fix: func() error {
+			return fs.ArchiveTask(t)
+		}})
+		return // archiving gives the file a fresh name
+	}
+
+	slug := slugify(t.Task.Title)
+	name := filepath.Base(t.FilePath)
+	prefix := fileNamePrefix.FindString(name)
+	base := strings.TrimPrefix(name, prefix)
+	if slug != "" && base != slug+".md" && fileNameCounter.ReplaceAllString(base, ".md") != slug+".md" {
+		if prefix == "" && !t.Task.CreatedAt.IsZero() {
+			prefix = t.Task.CreatedAt.Format(fileTimestampLayout) + "-"
+		}
+		newPath := filepath.Join(filepath.Dir(t.FilePath), prefix+slug+".md")
+		p := &problem{Path: t.FilePath, Kind: problemFileName, Reason: fmt.Sprintf("file name does not match title %q", t.Task.Title)}
+		if _, err := os.Stat(newPath); os.IsNotExist(err) {
+			p.Reason += ", will be renamed to " + filepath.Base(newPath)
+			p.fix = func() error { return fs.renameTaskFile(t, newPath) }
+		}
+		report.add(p)
+	}
+}
+
+// checkDuplicateIDs reports tasks sharing an ID. Active tasks keep their ID
+// over archived or deleted ones, and older tasks over newer ones; the rest
+// get new IDs.
+func (fs *FileStore) checkDuplicateIDs(report *doctorReport, all []*TaskWithPath) {
+	archived := func(t *TaskWithPath) bool {
+		rel, _ := filepath.Rel(fs.basePath, t.FilePath)
+		return !strings.HasPrefix(rel, TasksDir+string(filepath.Separator))
+	}
+	byID := make(map[string][]*TaskWithPath)
+	for _, t := range all {
+		if t.Task.ID != "" {
+			byID[t.Task.ID] = append(byID[t.Task.ID], t)
+		}
+	}
+	for id, tasks := range byID {
+		if len(tasks) < 2 {
+			continue
+		}
+		sort.SliceStable(tasks, func(i, j int) bool {
+			if archived(tasks[i]) != archived(tasks[j]) {
+				return !archived(tasks[i])
+			}
+			return tasks[i].Task.CreatedAt.Before(tasks[j].Task.CreatedAt)
+		})
+		for _, t := range tasks[1:] {
+			t := t
+			report.add(&problem{Path: t.FilePath, Kind: problemDuplicateID, Reason: fmt.Sprintf("ID %s is also used by %s, will get a new ID", id, fs.displayPath(tasks[0].FilePath)), fix: func() error {
+				return fs.withLock(func() error {
+					if err := checkUnchanged(t); err != nil {
+						return err
+					}
+					taken, err := fs.taskIDs()
+					if err != nil {
+						return err
+					}
+					content, err := os.ReadFile(t.FilePath)
+					if err != nil {
+						return err
+					}
+					newID := newTaskID(taken)
+					updated, err := withTaskID(content, newID)
+					if err != nil {
+						return err
+					}
+					if err := writeFileAtomic(t.FilePath, updated, 0644); err != nil {
+						return err
+					}
+					fs.record(fmt.Sprintf("new ID for %q", t.Task.Title), fs.change(t.FilePath, content, updated))
+					t.Task.ID = newID
+					t.Hash = contentHash(updated)
+					return nil
+				})
+			}})
+		}
+	}
+}
+
+// checkDuplicateTitles reports active tasks with the same title in a topic,
+// which makes them impossible to address by title. Telling them apart needs
+// a human, so there is no fix.
+func (fs *FileStore) checkDuplicateTitles(report *doctorReport, active map[string][]*TaskWithPath) {
+	for topic, tasks := range active {
+		seen := make(map[string]*TaskWithPath)
+		for _, t := range tasks {
+			key := strings.ToLower(t.Task.Title)
+			if first, ok := seen[key]; ok {
+				topicDisplay := topic
+				if topicDisplay == "" {
+					topicDisplay = "."
+				}
+				report.add(&problem{Path: t.FilePath, Kind: problemDuplicateTitle, Reason: fmt.Sprintf("title %q is also used by %s in topic %s", t.Task.Title, fs.displayPath(first.FilePath), topicDisplay)})
+				continue
+			}
+			seen[key] = t
+		}
+	}
+}
+
+// checkEmptyTopic reports a topic directory without any entries.
+func (fs *FileStore) checkEmptyTopic(report *doctorReport, dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) > 0 {
+		return
+	}
+	report.add(&problem{Path: dir, Kind: problemEmptyTopic, Reason: "topic directory is empty", fix: func() error {
+		return fs.withLock(func() error {
+			// Remove refuses to delete a directory that is no longer empty
+			return os.Remove(dir)
+		})
+	}})
+}
+
+// removeTempFile returns a fix deleting a leftover temporary file. Writers
+// hold the lock while their temporary files exist, so under the lock any
+// temporary file is abandoned.
+func (fs *FileStore) removeTempFile(path string) func() error {
+	return func() error {
+		return fs.withLock(func() error {
+			return os.Remove(path)
+		})
+	}
+}
+
+// renameTaskFile renames t's file within its directory.
+func (fs *FileStore) renameTaskFile(t *TaskWithPath, newPath string) error {
+	return fs.withLock(func() error {
+		if err := checkUnchanged(t); err != nil {
+			return err
+		}
+		if _, err := os.Stat(newPath); err == nil {
+			return fmt.Errorf("%s already exists", newPath)
+		}
+		content := snapshot(t.FilePath)
+		if err := renameDurable(t.FilePath, newPath); err != nil {
+			return fmt.Errorf("failed to rename task file: %w", err)
+		}
+		fs.record(fmt.Sprintf("rename %q", t.Task.Title), fs.change(t.FilePath, content, nil), fs.change(newPath, nil, content))
+		t.FilePath = newPath
+		return nil
+	})
+}
+
+// normalizeStatus maps common misspellings of a status, such as "Done" or
+// "in_progress", to the status they mean.
+func normalizeStatus(s string) (TaskStatus, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(s))
+	normalized = strings.NewReplacer("_", "-", " ", "-").Replace(normalized)
+	switch normalized {
+	case "canceled":
+		normalized = string(StatusCancelled)
+	case "inprogress", "doing", "started":
+		normalized = string(StatusInProgress)
+	}
+	status := TaskStatus(normalized)
+	return status, status.isValid()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDoctor(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+	store.SaveTask("work", &Task{Title: "Healthy", Status: StatusTodo, Priority: 3})
+	tasks, _ := store.LoadAllTasks()
+	healthy := tasks["work"][0]
+
+	write := func(rel, content string) {
+		path := filepath.Join(dir, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	write("tasks/work/20260101-090000-broken.md", "no frontmatter here")
+	write("tasks/work/20260101-090000-shouting.md", "---\nid: aaaa0001\ntitle: Shouting\nstatus: DONE_NOW\n---\n")
+	write("tasks/work/20260101-090000-typo.md", "---\nid: aaaa0002\ntitle: Typo\nstatus: In_Progress\n---\n")
+	write("tasks/work/20260101-090000-finished.md", "---\nid: aaaa0003\ntitle: Finished\nstatus: done\n---\n")
+	write("tasks/work/20260101-090000-old-name.md", "---\nid: aaaa0004\ntitle: New name\nstatus: todo\n---\n")
+	write("tasks/work/20260101-090001-healthy.md", "---\nid: "+healthy.Task.ID+"\ntitle: healthy\nstatus: todo\ncreated_at: 2030-01-01T00:00:00Z\n---\n")
+	write("tasks/work/.x.md"+tempMarker+"1", "---\ntitle: half")
+	os.MkdirAll(filepath.Join(dir, "tasks", "empty"), 0755)
+
+	// Unfixed problems fail the command, with only the report on stdout
+	var stdout strings.Builder
+	cmd := NewDoctorCmd(store)
+	cmd.SetArgs([]string{"-o", "json"})
+	cmd.SetOut(&stdout)
+	cmd.SetErr(io.Discard)
+	if err := cmd.Execute(); exitCode(err) != exitFailure {
+		t.Errorf("Expected doctor to fail while problems remain, got %v", err)
+	}
+	out := stdout.String()
+	var report doctorReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("Expected JSON output, got %v: %s", err, out)
+	}
+	kinds := make(map[string]*problem)
+	for _, p := range report.Problems {
+		kinds[filepath.Base(p.Path)+" "+p.Kind] = p
+		kinds["* "+p.Kind] = p
+	}
+	for key, fixable := range map[string]bool{
+		"20260101-090000-broken.md malformed":             false,
+		"20260101-090000-shouting.md invalid-status":      false,
+		"20260101-090000-typo.md invalid-status":          true,
+		"20260101-090000-finished.md closed-not-archived": true,
+		"20260101-090000-old-name.md filename-drift":      true,
+		"20260101-090001-healthy.md duplicate-id":         true,
+		"* duplicate-title":                               false,
+		"empty empty-topic":                               true,
+		".x.md" + tempMarker + "1 temp-file":              true,
+	} {
+		p, ok := kinds[key]
+		if !ok {
+			t.Errorf("Expected problem %q, got %s", key, out)
+			continue
+		}
+		if p.Fixable != fixable {
+			t.Errorf("Expected %q fixable=%v", key, fixable)
+		}
+	}
+	if len(report.Problems) != 9 {
+		t.Errorf("Expected 9 problems, got %d: %s", len(report.Problems), out)
+	}
+	if reason := kinds["* duplicate-title"].Reason; !strings.Contains(reason, "used by "+filepath.Join(filepath.Base(dir), "tasks", "work")) {
+		t.Errorf("Expected the other task's path relative to the project, got %q", reason)
+	}
+
+	out = runWithStore(NewDoctorCmd(store), "--fix")
+	if !strings.Contains(out, "(fixed)") || !strings.Contains(out, "6 fixed") {
+		t.Errorf("Expected repairs to be reported, got: %s", out)
+	}
+
+	// Only the problems that need a human are left
+	out = runWithStore(NewDoctorCmd(store))
+	if !strings.Contains(out, "3 problems") || !strings.Contains(out, "malformed") || !strings.Contains(out, "DONE_NOW") || !strings.Contains(out, "duplicate-title") || !strings.Contains(out, "3 problems remain.") {
+		t.Errorf("Expected only unfixable problems to remain, got: %s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tasks", "work", "20260101-090000-new-name.md")); err != nil {
+		t.Errorf("Expected the drifted file to be renamed: %v", err)
+	}
+	archived, _ := store.LoadArchivedTasks()
+	if len(archived["work"]) != 1 || archived["work"][0].Task.Title != "Finished" {
+		t.Errorf("Expected the done task to be archived, got %v", archived["work"])
+	}
+	tasks, _ = store.LoadAllTasks()
+	ids := make(map[string]bool)
+	for _, task := range tasks["work"] {
+		if task.Task.Title == "Typo" && task.Task.Status != StatusInProgress {
+			t.Errorf("Expected the status to be normalized, got %s", task.Task.Status)
+		}
+		if ids[task.Task.ID] {
+			t.Errorf("Expected unique IDs, got %s twice", task.Task.ID)
+		}
+		ids[task.Task.ID] = true
+	}
+
+	empty := t.TempDir()
+	if out := runWithStore(NewDoctorCmd(NewFileStore(empty))); !strings.Contains(out, "No problems found") {
+		t.Errorf("Expected a clean report, got: %s", out)
+	}
+	cmd = NewDoctorCmd(NewFileStore(empty))
+	cmd.SetArgs(nil)
+	cmd.SetOut(io.Discard)
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("Expected a clean run to succeed, got %v", err)
+	}
+}
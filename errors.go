@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of tada. They are part of the CLI contract, so scripts can
+// tell why a command failed; keep them in sync with the README.
+const (
+	exitFailure      = 1 // any other error, such as an unknown command
+	exitInvalidInput = 2 // bad arguments or flag values
+	exitNotFound     = 3 // no task matches the reference
+	exitAmbiguous    = 4 // the reference matches more than one task
+	exitStorage      = 5 // task files could not be read or written
+)
+
+// errInvalidInput and errStorage classify command errors that have no more
+// specific cause.
+var (
+	errInvalidInput = errors.New("invalid input")
+	errStorage      = errors.New("storage failure")
+)
+
+// commandError is an error a command has already shown to the user. It
+// wraps the cause, which decides the exit code.
+type commandError struct {
+	msg string
+	err error
+}
+
+func (e *commandError) Error() string { return e.msg }
+func (e *commandError) Unwrap() error { return e.err }
+
+// fail prints msg as a styled error on the command's stderr and returns it
+// as a commandError caused by err, so the command exits non-zero without
+// cobra printing the error or usage again.
+func fail(cmd *cobra.Command, err error, msg string) error {
+	styledErr := lipgloss.NewStyle().Foreground(cliError).Render(msg)
+	fmt.Fprintln(cmd.ErrOrStderr(), styledErr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return &commandError{msg: msg, err: err}
+}
+
+// inputError marks an error reported by cobra, such as a bad flag value or
+// a missing argument, as invalid input without changing its message.
+type inputError struct{ error }
+
+func (e inputError) Unwrap() []error { return []error{e.error, errInvalidInput} }
+
+// exitCode maps a command error to tada's exit code.
+func exitCode(err error) int {
+	var pathErr *fs.PathError
+	var linkErr *os.LinkError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errTaskNotFound):
+		return exitNotFound
+	case errors.Is(err, errAmbiguous):
+		return exitAmbiguous
+	case errors.Is(err, errInvalidInput), errors.Is(err, errInvalidTransition):
+		return exitInvalidInput
+	case errors.Is(err, errStorage), errors.Is(err, errConflict), errors.As(err, &pathErr), errors.As(err, &linkErr):
+		return exitStorage
+	}
+	return exitFailure
+}
+
+// execute runs root with run, fang.Execute in main, and returns the exit
+// code. Commands print their own errors; the error they return only decides
+// the code.
+func execute(root *cobra.Command, run func(*cobra.Command) error) int {
+	var failed error
+	silenceReported(root, &failed)
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return inputError{err}
+	})
+	if err := run(root); err != nil {
+		return exitCode(err)
+	}
+	return exitCode(failed)
+}
+
+// silenceReported wraps the commands below cmd so that errors they already
+// printed through fail are not printed again by fang. The first such error
+// is kept in failed to pick the exit code. Argument errors are marked as
+// invalid input.
+func silenceReported(cmd *cobra.Command, failed *error) {
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(c *cobra.Command, args []string) error {
+			err := run(c, args)
+			var reported *commandError
+			if errors.As(err, &reported) {
+				if *failed == nil {
+					*failed = err
+				}
+				return nil
+			}
+			return err
+		}
+	}
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(c *cobra.Command, args []string) error {
+			if err := validate(c, args); err != nil {
+				return inputError{err}
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		silenceReported(sub, failed)
+	}
+}
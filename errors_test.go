@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// runExit runs args against a root command like main does and returns the
+// exit code and the combined output.
+func runExit(store Storage, args ...string) (int, string) {
+	root := &cobra.Command{Use: "tada"}
+	root.AddCommand(NewAddCmd(store), NewEditCmd(store), NewCompleteCmd(store), NewShowCmd(store), NewListCmd(store, nil), NewBulkCmd(store))
+	var out strings.Builder
+	root.SetArgs(args)
+	root.SetOut(&out)
+	root.SetErr(&out)
+	code := execute(root, (*cobra.Command).Execute)
+	return code, out.String()
+}
+
+func TestExitCodes(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{ID: "abcd1111", Title: "First", Status: StatusTodo})
+	store.SaveTask("work", &Task{ID: "abcd2222", Title: "Second", Status: StatusTodo})
+
+	for _, tc := range []struct {
+		args []string
+		code int
+		msg  string
+	}{
+		{[]string{"show", "work/First"}, 0, "First"},
+		{[]string{"add", "work/Third", "--status", "banana"}, exitInvalidInput, "unknown status"},
+		{[]string{"add", "work/Third", "-p", "-1"}, exitInvalidInput, "priority must be 0 or a positive whole number"},
+		{[]string{"add", "work/Third", "--due", "someday"}, exitInvalidInput, "Invalid --due"},
+		{[]string{"add"}, exitInvalidInput, "requires at least 1 arg"},
+		{[]string{"list", "--status", "doing"}, exitInvalidInput, "unknown status"},
+		{[]string{"edit", "work/First", "--status", "nope"}, exitInvalidInput, "unknown status"},
+		{[]string{"complete", "work/Missing"}, exitNotFound, "Task not found"},
+		{[]string{"bulk", "--delete", "--search", "missing"}, exitNotFound, "No matching tasks found"},
+		{[]string{"show", "abcd"}, exitAmbiguous, `"abcd" matches 2 tasks`},
+		{[]string{"notacommand"}, exitFailure, "unknown command"},
+	} {
+		code, out := runExit(store, tc.args...)
+		if code != tc.code || !strings.Contains(out, tc.msg) {
+			t.Errorf("tada %s: expected exit %d with %q, got %d: %s", strings.Join(tc.args, " "), tc.code, tc.msg, code, out)
+		}
+	}
+	if tasks, _ := store.LoadAllTasks(); len(tasks["work"]) != 2 {
+		t.Errorf("Expected invalid input to add no task, got %d tasks", len(tasks["work"]))
+	}
+
+	// The message is printed once, by the command
+	if _, out := runExit(store, "complete", "work/Missing"); strings.Count(out, "Task not found") != 1 || strings.Contains(out, "Usage:") {
+		t.Errorf("Expected a single error without usage, got: %s", out)
+	}
+}
+
+func TestExitCodeForStorageErrors(t *testing.T) {
+	_, statErr := os.Stat("/nonexistent/tada")
+	for _, err := range []error{
+		statErr,
+		fmt.Errorf("save: %w", errConflict),
+		&commandError{msg: "Error loading tasks", err: errStorage},
+	} {
+		if code := exitCode(err); code != exitStorage {
+			t.Errorf("Expected %v to exit %d, got %d", err, exitStorage, code)
+		}
+	}
+	if code := exitCode(fmt.Errorf("%w: todo → done", errInvalidTransition)); code != exitInvalidInput {
+		t.Errorf("Expected a rejected transition to be invalid input, got %d", code)
+	}
+	if code := exitCode(io.ErrUnexpectedEOF); code != exitFailure {
+		t.Errorf("Expected other errors to exit %d, got %d", exitFailure, code)
+	}
+}
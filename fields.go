@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldDef declares a custom task field in the config.
+type FieldDef struct {
+	Name string `yaml:"name"`
+	// Type is one of string, int, enum, date, bool or url.
+	Type string `yaml:"type"`
+	// Values lists the allowed values of an enum field.
+	Values []string `yaml:"values,omitempty"`
+}
+
+// customFields are the fields declared in the config, in order.
+var customFields []FieldDef
+
+var (
+	fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+	fieldTypes       = map[string]bool{"string": true, "int": true, "enum": true, "date": true, "bool": true, "url": true}
+	// builtinSortKeys cannot be field names, since tada list --sort takes
+	// both. Neither can queryKeys, which the query language checks first.
+	builtinSortKeys = map[string]bool{"created": true, "priority": true, "title": true, "status": true, "due": true}
+)
+
+// setFields makes defs the declared custom fields. Invalid declarations are
+// rejected and the current ones kept.
+func setFields(defs []FieldDef) error {
+	seen := make(map[string]bool)
+	for _, f := range defs {
+		switch {
+		case !fieldNamePattern.MatchString(f.Name):
+			return fmt.Errorf("invalid field name %q: use lowercase letters, digits, - and _", f.Name)
+		case builtinSortKeys[f.Name] || slices.Contains(queryKeys, f.Name):
+			return fmt.Errorf("field name %q is reserved", f.Name)
+		case seen[f.Name]:
+			return fmt.Errorf("field %q is declared twice", f.Name)
+		case !fieldTypes[f.Type]:
+			return fmt.Errorf("field %q has unknown type %q (use string, int, enum, date, bool or url)", f.Name, f.Type)
+		case f.Type == "enum" && len(f.Values) == 0:
+			return fmt.Errorf("enum field %q has no values", f.Name)
+		}
+		seen[f.Name] = true
+	}
+	customFields = defs
+	return nil
+}
+
+// fieldDef returns the declaration of the named custom field.
+func fieldDef(name string) (FieldDef, bool) {
+	for _, f := range customFields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return FieldDef{}, false
+}
+
+// normalize validates a value for the field and returns it in the form it
+// is stored: integers without padding, dates as YYYY-MM-DD, booleans as
+// true or false, and enum values spelled as declared.
+func (f FieldDef) normalize(value string, now time.Time) (string, error) {
+	value = strings.TrimSpace(value)
+	switch f.Type {
+	case "int":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("field %s: %q is not a whole number", f.Name, value)
+		}
+		return strconv.Itoa(n), nil
+	case "enum":
+		for _, v := range f.Values {
+			if strings.EqualFold(v, value) {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("field %s: %q is not one of %s", f.Name, value, strings.Join(f.Values, ", "))
+	case "date":
+		t, err := parseDate(value, now)
+		if err != nil {
+			return "", fmt.Errorf("field %s: %v", f.Name, err)
+		}
+		return t.Format(dateLayout), nil
+	case "bool":
+		b, err := strconv.ParseBool(strings.ToLower(value))
+		if err != nil {
+			return "", fmt.Errorf("field %s: %q is not true or false", f.Name, value)
+		}
+		return strconv.FormatBool(b), nil
+	case "url":
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "", fmt.Errorf("field %s: %q is not an absolute URL", f.Name, value)
+		}
+		return value, nil
+	}
+	return value, nil
+}
+
+// choices returns the values h/l cycle through in the TUI, or nil for
+// free-text fields.
+func (f FieldDef) choices() []string {
+	switch f.Type {
+	case "enum":
+		return f.Values
+	case "bool":
+		return []string{"true", "false"}
+	}
+	return nil
+}
+
+// less orders two stored values of the field; empty values sort last.
+func (f FieldDef) less(a, b string) bool {
+	if a == "" || b == "" {
+		return a != "" && b == ""
+	}
+	switch f.Type {
+	case "int":
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x < y
+	case "enum":
+		return choiceIndex(f.Values, a) < choiceIndex(f.Values, b)
+	}
+	return a < b
+}
+
+// choiceIndex returns the position of s in values, or len(values).
+func choiceIndex(values []string, s string) int {
+	for i, v := range values {
+		if v == s {
+			return i
+		}
+	}
+	return len(values)
+}
+
+// parseFieldFlags parses --field key=value arguments into validated,
+// normalized values. An empty value means the field is to be cleared.
+func parseFieldFlags(flags []string, now time.Time) (map[string]string, error) {
+	raw := make(map[string]string)
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not key=value", flag)
+		}
+		raw[strings.TrimSpace(key)] = value
+	}
+	return normalizeFields(raw, now)
+}
+
+// normalizeFields validates and normalizes raw values of declared fields.
+// Empty values are kept empty, meaning the field is to be cleared.
+func normalizeFields(raw map[string]string, now time.Time) (map[string]string, error) {
+	values := make(map[string]string)
+	for key, value := range raw {
+		f, known := fieldDef(key)
+		if !known {
+			return nil, fmt.Errorf("unknown field %q (declared: %s)", key, fieldNames())
+		}
+		if strings.TrimSpace(value) == "" {
+			values[key] = ""
+			continue
+		}
+		normalized, err := f.normalize(value, now)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = normalized
+	}
+	return values, nil
+}
+
+// setFieldValues applies parsed field values to the task, removing those
+// that are empty.
+func (t *Task) setFieldValues(values map[string]string) {
+	for key, value := range values {
+		if value == "" {
+			delete(t.Fields, key)
+			continue
+		}
+		if t.Fields == nil {
+			t.Fields = make(map[string]string)
+		}
+		t.Fields[key] = value
+	}
+	if len(t.Fields) == 0 {
+		t.Fields = nil
+	}
+}
+
+// fieldNames lists the declared fields for messages.
+func fieldNames() string {
+	if len(customFields) == 0 {
+		return "none; declare them under fields in the config"
+	}
+	names := make([]string, len(customFields))
+	for i, f := range customFields {
+		names[i] = f.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// fieldKeys returns the task's field names, declared ones first in config
+// order, then any others found in the file.
+func (t *Task) fieldKeys() []string {
+	var keys, others []string
+	for _, f := range customFields {
+		if _, ok := t.Fields[f.Name]; ok {
+			keys = append(keys, f.Name)
+		}
+	}
+	for key := range t.Fields {
+		if _, declared := fieldDef(key); !declared {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	return append(keys, others...)
+}
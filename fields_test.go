@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func useFields(t *testing.T, defs []FieldDef) {
+	t.Helper()
+	if err := setFields(defs); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { setFields(nil) })
+}
+
+var teamFields = []FieldDef{
+	{Name: "estimate", Type: "int"},
+	{Name: "component", Type: "enum", Values: []string{"api", "ui", "docs"}},
+	{Name: "ticket", Type: "url"},
+	{Name: "deadline", Type: "date"},
+	{Name: "billable", Type: "bool"},
+}
+
+func TestSetFieldsValidates(t *testing.T) {
+	t.Cleanup(func() { setFields(nil) })
+	for _, defs := range [][]FieldDef{
+		{{Name: "Estimate", Type: "int"}},
+		{{Name: "priority", Type: "int"}},
+		{{Name: "tag", Type: "string"}},
+		{{Name: "scheduled", Type: "date"}},
+		{{Name: "size", Type: "float"}},
+		{{Name: "size", Type: "enum"}},
+		{{Name: "size", Type: "int"}, {Name: "size", Type: "string"}},
+	} {
+		if err := setFields(defs); err == nil {
+			t.Errorf("Expected %+v to be rejected", defs)
+		}
+	}
+}
+
+func TestCustomFieldsCommands(t *testing.T) {
+	useFields(t, teamFields)
+	store := NewFileStore(t.TempDir())
+
+	out := runWithStore(NewAddCmd(store), "work/Login page", "--field", "estimate=05", "--field", "component=UI", "--field", "deadline=2026-11-01", "--field", "billable=yes")
+	if !strings.Contains(out, "is not true or false") {
+		t.Errorf("Expected the bool to be validated, got: %s", out)
+	}
+	for _, bad := range []string{"estimate=two", "component=infra", "ticket=JIRA-12", "customer=acme", "estimate"} {
+		if out := runWithStore(NewAddCmd(store), "work/Bad", "--field", bad); !strings.Contains(out, "Invalid --field") {
+			t.Errorf("Expected --field %s to be rejected, got: %s", bad, out)
+		}
+	}
+	if tasks, _ := store.LoadAllTasks(); len(tasks["work"]) != 0 {
+		t.Fatalf("Expected invalid tasks not to be added, got %d", len(tasks["work"]))
+	}
+
+	runWithStore(NewAddCmd(store), "work/Login page", "--field", "estimate=05", "--field", "component=UI", "--field", "deadline=2026-11-01", "--field", "billable=TRUE")
+	runWithStore(NewAddCmd(store), "work/API docs", "--field", "estimate=2", "--field", "component=docs", "--field", "ticket=https://example.com/T-1")
+	runWithStore(NewAddCmd(store), "work/Refactor")
+
+	tasks, _ := store.LoadAllTasks()
+	var login *TaskWithPath
+	for _, task := range tasks["work"] {
+		if task.Task.Title == "Login page" {
+			login = task
+		}
+	}
+	data, _ := os.ReadFile(login.FilePath)
+	if !strings.Contains(string(data), "fields:\n    billable: \"true\"\n    component: ui\n    deadline: \"2026-11-01\"\n    estimate: \"5\"\n") {
+		t.Errorf("Expected normalized fields in the frontmatter, got:\n%s", data)
+	}
+
+	runWithStore(NewEditCmd(store), login.Task.ID, "--field", "billable=", "--field", "estimate=8")
+	if out := runWithStore(NewShowCmd(store), login.Task.ID); !strings.Contains(out, "estimate: 8") || !strings.Contains(out, "deadline: 2026-11-01") || strings.Contains(out, "billable") {
+		t.Errorf("Expected the edited fields in show, got: %s", out)
+	}
+
+	out = runWithStore(NewListCmd(store, nil), "--field", "component=ui", "--simple")
+	if !strings.Contains(out, "Login page") || strings.Contains(out, "API docs") {
+		t.Errorf("Expected only the ui task, got: %s", out)
+	}
+	out = runWithStore(NewListCmd(store, nil), "--sort", "estimate", "--simple")
+	if a, b, c := strings.Index(out, "API docs"), strings.Index(out, "Login page"), strings.Index(out, "Refactor"); !(a < b && b < c) {
+		t.Errorf("Expected tasks by estimate with unset last, got: %s", out)
+	}
+
+	var buf bytes.Buffer
+	tasks, _ = store.LoadAllTasks()
+	for _, task := range tasks["work"] {
+		if task.Task.Title == "API docs" {
+			buf.WriteString(strings.Join(csvRow(task.Task), ","))
+		}
+	}
+	if header := strings.Join(csvHeader(), ","); !strings.HasSuffix(header, "CompletedAt,estimate,component,ticket,deadline,billable") {
+		t.Errorf("Expected a column per field, got %s", header)
+	}
+	if !strings.HasSuffix(buf.String(), ",2,docs,https://example.com/T-1,,") {
+		t.Errorf("Expected the field values as columns, got %s", buf.String())
+	}
+}
+
+func TestTUIEditCustomFields(t *testing.T) {
+	useFields(t, teamFields[:2])
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("", &Task{Title: "Form task", Status: StatusTodo})
+	tasks, _ := store.LoadAllTasks()
+
+	m := model{tasks: tasks, store: store, expanded: map[string]bool{}, mode: editView, editTask: tasks[""][0]}
+	m.initForm()
+	press := func(keys ...string) {
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			if k == "tab" {
+				msg = tea.KeyMsg{Type: tea.KeyTab}
+			}
+			next, _ := m.updateEditView(msg)
+			m = next.(model)
+		}
+	}
+	press("tab", "tab", "tab", "tab", "tab", "x")
+	if m.editForm.fields["estimate"] != "x" || !strings.Contains(m.viewEdit(), "estimate: x█") {
+		t.Fatalf("Expected to type into the estimate field, got:\n%s", m.viewEdit())
+	}
+	press("tab", "l", "l")
+	if m.editForm.fields["component"] != "ui" {
+		t.Errorf("Expected l to cycle the enum, got %q", m.editForm.fields["component"])
+	}
+
+	m.editForm.field = fieldSave
+	next, _ := m.saveTask()
+	m = next.(model)
+	if m.mode != editView || !strings.Contains(m.editWarning, "not a whole number") {
+		t.Fatalf("Expected the invalid estimate to keep the form open, got %q", m.editWarning)
+	}
+	m.editForm.fields["estimate"] = "3"
+	m.saveTask()
+	if task, _ := store.GetTask(tasks[""][0].Task.ID); task.Task.Fields["estimate"] != "3" || task.Task.Fields["component"] != "ui" {
+		t.Errorf("Expected the fields to be saved, got %v", task.Task.Fields)
+	}
+}
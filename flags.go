@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// statusValue is a --status flag that only accepts statuses of the active
+// workflow, so a typo is rejected while parsing the command line, before
+// any task is touched.
+type statusValue string
+
+func (v *statusValue) String() string { return string(*v) }
+
+func (v *statusValue) Set(s string) error {
+	if !TaskStatus(s).isValid() {
+		return fmt.Errorf("unknown status (use %s)", statusNames())
+	}
+	*v = statusValue(s)
+	return nil
+}
+
+// Type is string so the flag is read with GetString like any other.
+func (v *statusValue) Type() string { return "string" }
+
+// priorityValue is a --priority flag that rejects negative priorities.
+type priorityValue int
+
+func newPriorityValue(p int) *priorityValue {
+	v := priorityValue(p)
+	return &v
+}
+
+func (v *priorityValue) String() string { return strconv.Itoa(int(*v)) }
+
+func (v *priorityValue) Set(s string) error {
+	p, err := strconv.Atoi(s)
+	if err != nil || p < 0 {
+		return fmt.Errorf("priority must be 0 or a positive whole number")
+	}
+	*v = priorityValue(p)
+	return nil
+}
+
+// Type is int so the flag is read with GetInt like any other.
+func (v *priorityValue) Type() string { return "int" }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// taskKeys are the frontmatter keys tada manages, taken from the Task
+// struct's yaml tags. Any other key belongs to another tool.
+var taskKeys = func() map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(Task{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}()
+
+// marshalFrontmatter renders the task's frontmatter. When the task was read
+// from a file, its original frontmatter is updated in place: keys keep their
+// order and comments, unchanged values keep their formatting, keys tada does
+// not know are kept as they were, cleared keys are removed and new keys are
+// appended.
+func marshalFrontmatter(task *Task) []byte {
+	fresh, err := yaml.Marshal(task)
+	if err != nil || task.Frontmatter == "" {
+		return fresh
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(task.Frontmatter), &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fresh
+	}
+	var update yaml.Node
+	if err := update.Encode(task); err != nil || update.Kind != yaml.MappingNode {
+		return fresh
+	}
+
+	values := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(update.Content); i += 2 {
+		values[update.Content[i].Value] = update.Content[i+1]
+	}
+	original := doc.Content[0]
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(original.Content); i += 2 {
+		key, value := original.Content[i], original.Content[i+1]
+		if !taskKeys[key.Value] {
+			merged = append(merged, key, value)
+			continue
+		}
+		next, ok := values[key.Value]
+		if !ok {
+			continue // cleared
+		}
+		delete(values, key.Value)
+		if !sameValue(value, next) {
+			next.HeadComment, next.LineComment, next.FootComment = value.HeadComment, value.LineComment, value.FootComment
+			value = next
+		}
+		merged = append(merged, key, value)
+	}
+	for i := 0; i+1 < len(update.Content); i += 2 {
+		if _, added := values[update.Content[i].Value]; added {
+			merged = append(merged, update.Content[i], update.Content[i+1])
+		}
+	}
+	original.Content = merged
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(&doc); err != nil {
+		return fresh
+	}
+	enc.Close()
+	return buf.Bytes()
+}
+
+// sameValue reports whether two YAML nodes decode to the same value.
+func sameValue(a, b *yaml.Node) bool {
+	var x, y interface{}
+	if a.Decode(&x) != nil || b.Decode(&y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
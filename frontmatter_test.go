@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sharedTaskFile = `---
+# Managed by tada and Obsidian
+title: Shared note
+aliases: [shared, note]
+status: todo # set by the team
+id: 5a5a5a5a
+tags: [a, b]
+due: 2026-11-01T00:00:00Z
+obsidian:
+    cssclass: wide
+created_at: 2026-10-01T09:00:00Z
+---
+
+# Shared note
+`
+
+func TestFrontmatterRoundTrip(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	dir := filepath.Join(store.basePath, TasksDir, "notes")
+	os.MkdirAll(dir, 0755)
+	path := filepath.Join(dir, "20261001-090000-shared-note.md")
+	os.WriteFile(path, []byte(sharedTaskFile), 0644)
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(path, past, past)
+	store.LoadAllTasks() // the next load comes from the index
+
+	tasks, _ := store.LoadAllTasks()
+	task := tasks["notes"][0]
+	runWithStore(NewEditCmd(store), task.Task.ID, "--status", "in-progress", "--due", "none")
+
+	data, _ := os.ReadFile(path)
+	got := string(data)
+	for _, want := range []string{
+		"# Managed by tada and Obsidian\ntitle: Shared note\naliases: [shared, note]\nstatus: in-progress # set by the team\nid: 5a5a5a5a\ntags: [a, b]\nobsidian:\n    cssclass: wide\ncreated_at: 2026-10-01T09:00:00Z\n",
+		"updated_at: ",
+		"history:\n    - from: todo\n      to: in-progress\n",
+		"---\n\n# Shared note\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in the rewritten file, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "due:") {
+		t.Errorf("Expected the cleared due date to be removed, got:\n%s", got)
+	}
+
+	// Unknown keys also survive archiving, which rewrites and moves the file
+	tasks, _ = store.LoadAllTasks()
+	if err := store.ArchiveTask(tasks["notes"][0]); err != nil {
+		t.Fatal(err)
+	}
+	archived, _ := store.LoadArchivedTasks()
+	data, _ = os.ReadFile(archived["notes"][0].FilePath)
+	if !strings.Contains(string(data), "aliases: [shared, note]") || !strings.Contains(string(data), "status: done # set by the team") {
+		t.Errorf("Expected the archived file to keep its keys, got:\n%s", data)
+	}
+}
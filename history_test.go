@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusHistory(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	runWithStore(NewAddCmd(store), "Track me")
+	tasks, _ := store.LoadAllTasks()
+	task := tasks[""][0]
+	if len(task.Task.History) != 1 || task.Task.History[0].To != StatusTodo || task.Task.UpdatedAt == nil {
+		t.Fatalf("Expected the initial status to be recorded, got %+v", task.Task)
+	}
+	created := *task.Task.UpdatedAt
+
+	runWithStore(NewEditCmd(store), task.Task.ID, "--status", "in-progress")
+	runWithStore(NewEditCmd(store), task.Task.ID, "--status", "in-progress", "--priority", "1")
+
+	// The TUI cycles in-progress to done
+	tasks, _ = store.LoadAllTasks()
+	m := model{tasks: tasks, store: store, expanded: map[string]bool{}}
+	m.cycleTaskStatus(tasks[""][0], 1)
+
+	tasks, _ = store.LoadAllTasks()
+	history := tasks[""][0].Task.History
+	if len(history) != 3 || history[1].From != StatusTodo || history[1].To != StatusInProgress || history[2].To != StatusDone {
+		t.Fatalf("Expected todo → in-progress → done, got %+v", history)
+	}
+	if !tasks[""][0].Task.UpdatedAt.After(created) {
+		t.Errorf("Expected updated_at to move forward, got %v", tasks[""][0].Task.UpdatedAt)
+	}
+
+	if err := store.CompleteTask("", "Track me"); err != nil {
+		t.Fatal(err)
+	}
+	out := runWithStore(NewArchiveCmd(store, nil), "show", task.Task.ID)
+	for _, want := range []string{"Updated: ", "History:", "created as todo", "todo → in-progress (after ", "in-progress → done"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in the timeline, got: %s", want, out)
+		}
+	}
+
+	// Restoring reopens the task, which is recorded too
+	archived, _ := store.LoadArchivedTasks()
+	store.RestoreTask(archived[""][0])
+	tasks, _ = store.LoadAllTasks()
+	history = tasks[""][0].Task.History
+	if last := history[len(history)-1]; last.From != StatusDone || last.To != StatusTodo {
+		t.Errorf("Expected done → todo to be recorded, got %+v", last)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		90 * time.Second:            "1m",
+		2*time.Hour + 5*time.Minute: "2h 5m",
+		76 * time.Hour:              "3d 4h",
+		-time.Minute:                "0m",
+	} {
+		if got := formatDuration(d); got != want {
+			t.Errorf("formatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
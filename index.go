@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// indexFileName is the task cache in the .tada directory.
+const indexFileName = "index.json"
+
+// indexVersion is bumped whenever the cached Task layout changes, which
+// discards old indexes.
+const indexVersion = 6
+
+// taskIndex caches parsed task files so loading a large tree only has to
+// stat each file. Entries are keyed by path relative to the .tada
+// directory and trusted only while the file's size and modification time
+// still match.
+type taskIndex struct {
+	Version int `json:"version"`
+	// Scanned records, per walked directory, when the last walk that
+	// refreshed the index started. Files modified within racyWindow of it
+	// may have changed again without a visible timestamp change, so they
+	// are always re-read.
+	Scanned map[string]int64       `json:"scanned"`
+	Entries map[string]*indexEntry `json:"entries"`
+	// Dirs holds the modification time of every walked directory. While
+	// none of them changed, no task file was added, removed or renamed, so
+	// the lookup maps below cover every file.
+	Dirs map[string]int64 `json:"dirs"`
+	// IDs, Titles and Tags map task IDs, "[topic/]title" references and
+	// lowercased tags to entry keys. They are rebuilt from Entries whenever
+	// the index is saved.
+	IDs    map[string][]string `json:"ids"`
+	Titles map[string][]string `json:"titles"`
+	Tags   map[string][]string `json:"tags"`
+}
+
+// racyWindow covers file systems with coarse modification times.
+const racyWindow = int64(time.Second)
+
+type indexEntry struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"`
+	Hash    string `json:"hash"`
+	Task    *Task  `json:"task"`
+	// Frontmatter is the task's raw frontmatter, which Task does not
+	// serialize.
+	Frontmatter string `json:"frontmatter,omitempty"`
+}
+
+// loadIndex reads the index, returning an empty one when it is missing,
+// corrupted or from another version.
+func (fs *FileStore) loadIndex() *taskIndex {
+	empty := &taskIndex{Version: indexVersion, Scanned: make(map[string]int64), Entries: make(map[string]*indexEntry), Dirs: make(map[string]int64)}
+	data, err := os.ReadFile(filepath.Join(fs.basePath, indexFileName))
+	if err != nil {
+		return empty
+	}
+	var idx taskIndex
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Version != indexVersion || idx.Entries == nil || idx.Scanned == nil || idx.Dirs == nil {
+		return empty
+	}
+	return &idx
+}
+
+// saveIndex rebuilds the lookup maps and writes the index under the lock.
+// If the lock is busy, held by another process or by the operation this
+// load is part of, the write is skipped and left to the next load.
+// Failures are ignored: the index is only a cache and is rebuilt on the
+// next load.
+func (fs *FileStore) saveIndex(idx *taskIndex) {
+	idx.rebuildMaps()
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return
+	}
+	_, _ = fs.tryWithLock(func() error {
+		return writeFileAtomic(filepath.Join(fs.basePath, indexFileName), data, 0644)
+	})
+}
+
+// rebuildMaps recomputes IDs, Titles and Tags from the entries.
+func (idx *taskIndex) rebuildMaps() {
+	idx.IDs = make(map[string][]string)
+	idx.Titles = make(map[string][]string)
+	idx.Tags = make(map[string][]string)
+	for key, e := range idx.Entries {
+		if e.Task == nil {
+			continue
+		}
+		if e.Task.ID != "" {
+			idx.IDs[e.Task.ID] = append(idx.IDs[e.Task.ID], key)
+		}
+		ref := entryRef(key, e.Task.Title)
+		idx.Titles[ref] = append(idx.Titles[ref], key)
+		for _, tag := range e.Task.Tags {
+			tag = strings.ToLower(tag)
+			idx.Tags[tag] = append(idx.Tags[tag], key)
+		}
+	}
+}
+
+// entryRef returns the "[topic/]title" reference of the task stored at key,
+// whose first element is the tasks, archive or trash directory.
+func entryRef(key, title string) string {
+	_, rel, _ := strings.Cut(key, "/")
+	if topic := path.Dir(rel); topic != "." {
+		return topic + "/" + title
+	}
+	return title
+}
+
+// indexKey returns path relative to the .tada directory, or false for
+// paths outside it.
+func (fs *FileStore) indexKey(path string) (string, bool) {
+	rel, err := filepath.Rel(fs.basePath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// cached returns the indexed task for key, found by walking the directory
+// root, if the entry still matches the file. Each load decodes its own
+// index, so the task is not shared.
+func (idx *taskIndex) cached(root, key string, info os.FileInfo) (*Task, string, bool) {
+	e, ok := idx.Entries[key]
+	if !ok || e.Task == nil || e.Size != info.Size() || e.ModTime != info.ModTime().UnixNano() || e.ModTime >= idx.Scanned[root]-racyWindow {
+		return nil, "", false
+	}
+	e.Task.Frontmatter = e.Frontmatter
+	return e.Task, e.Hash, true
+}
+
+// store records a freshly parsed task.
+func (idx *taskIndex) store(key string, info os.FileInfo, hash string, task *Task) {
+	idx.Entries[key] = &indexEntry{Size: info.Size(), ModTime: info.ModTime().UnixNano(), Hash: hash, Task: task, Frontmatter: task.Frontmatter}
+}
+
+// storeDir records a walked directory and reports whether it changed.
+func (idx *taskIndex) storeDir(key string, info os.FileInfo) bool {
+	mtime := info.ModTime().UnixNano()
+	if old, ok := idx.Dirs[key]; ok && old == mtime {
+		return false
+	}
+	idx.Dirs[key] = mtime
+	return true
+}
+
+// prune drops entries and directories below prefix that were not seen in
+// the last walk and reports whether any were removed.
+func (idx *taskIndex) prune(prefix string, seen map[string]bool) bool {
+	removed := false
+	for key := range idx.Entries {
+		if strings.HasPrefix(key, prefix) && !seen[key] {
+			delete(idx.Entries, key)
+			removed = true
+		}
+	}
+	for key := range idx.Dirs {
+		if strings.HasPrefix(key, prefix) && !seen[key] {
+			delete(idx.Dirs, key)
+			removed = true
+		}
+	}
+	return removed
+}
+
+// indexed returns the tasks stored at keys below the directory dir
+// (TasksDir, ArchiveDir or TrashDir), without walking it. ok is false if
+// the index cannot vouch for them: dir was never walked, or a directory
+// below it changed since, so files may have been added or removed. Files
+// that changed since they were indexed are read again, so callers must
+// check that each task still matches what they looked up.
+func (fs *FileStore) indexed(idx *taskIndex, dir string, keys []string) (tasks []*TaskWithPath, ok bool) {
+	scanned, walked := idx.Scanned[dir]
+	if !walked {
+		return nil, false
+	}
+	for key, mtime := range idx.Dirs {
+		if key != dir && !strings.HasPrefix(key, dir+"/") {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(fs.basePath, filepath.FromSlash(key)))
+		if err != nil || info.ModTime().UnixNano() != mtime || mtime >= scanned-racyWindow {
+			return nil, false
+		}
+	}
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, dir+"/") {
+			continue
+		}
+		filePath := filepath.Join(fs.basePath, filepath.FromSlash(key))
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return nil, false
+		}
+		topic := path.Dir(strings.TrimPrefix(key, dir+"/"))
+		if topic == "." {
+			topic = ""
+		}
+		if task, hash, cached := idx.cached(dir, key, info); cached {
+			tasks = append(tasks, &TaskWithPath{Task: task, FilePath: filePath, Topic: topic, Hash: hash})
+			continue
+		}
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, false
+		}
+		task, err := parseTask(content)
+		if err != nil {
+			continue
+		}
+		tasks = append(tasks, &TaskWithPath{Task: task, FilePath: filePath, Topic: topic, Hash: contentHash(content)})
+	}
+	return tasks, true
+}
+
+// lookup returns the active tasks for which match is true. keys picks the
+// candidates from the index maps, so only they are read. If the index
+// cannot vouch for them, or none of them matches, the whole task tree is
+// loaded instead; a miss may be a file edited in place since it was
+// indexed. GetTask and CompleteTask use it.
+func (fs *FileStore) lookup(keys func(*taskIndex) []string, match func(topic string, t *Task) bool) ([]*TaskWithPath, error) {
+	filter := func(tasks []*TaskWithPath) []*TaskWithPath {
+		var found []*TaskWithPath
+		for _, t := range tasks {
+			if match(t.Topic, t.Task) {
+				found = append(found, t)
+			}
+		}
+		return found
+	}
+
+	idx := fs.loadIndex()
+	if candidates, ok := fs.indexed(idx, TasksDir, keys(idx)); ok {
+		if found := filter(candidates); len(found) > 0 {
+			return found, nil
+		}
+	}
+	tasks, err := fs.LoadAllTasks()
+	if err != nil {
+		return nil, err
+	}
+	var all []*TaskWithPath
+	for _, list := range tasks {
+		all = append(all, list...)
+	}
+	return filter(all), nil
+}
+
+// findRef returns the tasks of a loaded tree that ref matches, with the
+// same precedence as findTasks, using the index maps instead of comparing
+// ref with every task. ok is false if the index does not cover the loaded
+// tasks, such as when it could not be written.
+func (fs *FileStore) findRef(tasks map[string][]*TaskWithPath, ref string) ([]*TaskWithPath, bool) {
+	idx := fs.loadIndex()
+	byKey, ok := fs.byKey(idx, tasks)
+	if !ok {
+		return nil, false
+	}
+	pick := func(keys []string, match func(*TaskWithPath) bool) []*TaskWithPath {
+		var found []*TaskWithPath
+		for _, key := range keys {
+			if t := byKey[key]; t != nil && match(t) {
+				found = append(found, t)
+			}
+		}
+		return found
+	}
+
+	if found := pick(idx.IDs[ref], func(t *TaskWithPath) bool { return t.Task.ID == ref }); len(found) > 0 {
+		return found[:1], true
+	}
+	topic, title := splitTopicTitle(ref)
+	matches := pick(idx.Titles[ref], func(t *TaskWithPath) bool { return t.Task.Title == title && t.Topic == topic })
+	if len(matches) == 0 && len(ref) >= minIDPrefix {
+		for id, keys := range idx.IDs {
+			if strings.HasPrefix(id, ref) {
+				matches = append(matches, pick(keys, func(t *TaskWithPath) bool { return t.Task.ID == id })...)
+			}
+		}
+	}
+	sortMatches(matches)
+	return matches, true
+}
+
+// findTagged returns the tasks of a loaded tree tagged tag, ignoring case,
+// using the index's tag map. ok is false if the index does not cover the
+// loaded tasks.
+func (fs *FileStore) findTagged(tasks map[string][]*TaskWithPath, tag string) ([]*TaskWithPath, bool) {
+	idx := fs.loadIndex()
+	byKey, ok := fs.byKey(idx, tasks)
+	if !ok {
+		return nil, false
+	}
+	var found []*TaskWithPath
+	for _, key := range idx.Tags[strings.ToLower(tag)] {
+		if t := byKey[key]; t != nil {
+			found = append(found, t)
+		}
+	}
+	return found, true
+}
+
+// byKey maps the loaded tasks by index key. ok is false unless every one
+// of them is indexed with the content it was loaded with.
+func (fs *FileStore) byKey(idx *taskIndex, tasks map[string][]*TaskWithPath) (map[string]*TaskWithPath, bool) {
+	byKey := make(map[string]*TaskWithPath)
+	for _, list := range tasks {
+		for _, t := range list {
+			key, ok := fs.indexKey(t.FilePath)
+			if e := idx.Entries[key]; !ok || e == nil || e.Hash != t.Hash {
+				return nil, false
+			}
+			byKey[key] = t
+		}
+	}
+	return byKey, true
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileStore_Index(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{Title: "Indexed", Status: StatusTodo, Priority: 3, Tags: []string{"a"}})
+	store.SaveTask("work", &Task{Title: "Removed", Status: StatusTodo, Priority: 3})
+	tasks, _ := store.LoadAllTasks()
+
+	// Age the files so they are outside the racy window
+	past := time.Now().Add(-time.Hour)
+	for _, task := range tasks["work"] {
+		os.Chtimes(task.FilePath, past, past)
+	}
+	store.LoadAllTasks()
+
+	indexPath := filepath.Join(store.basePath, indexFileName)
+	readIndex := func() *taskIndex {
+		var idx taskIndex
+		data, err := os.ReadFile(indexPath)
+		if err != nil {
+			t.Fatalf("Expected an index file: %v", err)
+		}
+		if err := json.Unmarshal(data, &idx); err != nil {
+			t.Fatalf("Expected a valid index: %v", err)
+		}
+		return &idx
+	}
+	idx := readIndex()
+	if len(idx.Entries) != 2 {
+		t.Fatalf("Expected 2 index entries, got %d", len(idx.Entries))
+	}
+
+	// Unchanged files are served from the index without being parsed
+	for _, e := range idx.Entries {
+		if e.Task.Title == "Indexed" {
+			e.Task.Description = "from the index"
+		}
+	}
+	data, _ := json.Marshal(idx)
+	os.WriteFile(indexPath, data, 0644)
+	tasks, _ = store.LoadAllTasks()
+	var indexed, removed *TaskWithPath
+	for _, task := range tasks["work"] {
+		switch task.Task.Title {
+		case "Indexed":
+			indexed = task
+		case "Removed":
+			removed = task
+		}
+	}
+	if indexed == nil || indexed.Task.Description != "from the index" || indexed.Hash == "" {
+		t.Fatalf("Expected the cached entry to be used, got %+v", indexed)
+	}
+
+	// A changed file is re-read
+	indexed.Task.Description = "edited"
+	if err := store.UpdateTask(indexed); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(removed.FilePath)
+	tasks, _ = store.LoadAllTasks()
+	if len(tasks["work"]) != 1 || tasks["work"][0].Task.Description != "edited" {
+		t.Errorf("Expected the edited file to be re-read, got %+v", tasks["work"])
+	}
+	if idx := readIndex(); len(idx.Entries) != 1 {
+		t.Errorf("Expected the deleted file to be pruned, got %d entries", len(idx.Entries))
+	}
+
+	// A corrupted index is rebuilt transparently
+	os.WriteFile(indexPath, []byte("{not json"), 0644)
+	tasks, err := store.LoadAllTasks()
+	if err != nil || len(tasks["work"]) != 1 {
+		t.Fatalf("Expected loading to survive a corrupted index, got %v, %v", tasks, err)
+	}
+	if idx := readIndex(); len(idx.Entries) != 1 {
+		t.Errorf("Expected the index to be rebuilt, got %d entries", len(idx.Entries))
+	}
+
+	// Lookups by ID and topic/title go through the index
+	if got, err := store.GetTask(tasks["work"][0].Task.ID); err != nil || got.Task.Title != "Indexed" {
+		t.Errorf("Expected GetTask to find the task, got %v, %v", got, err)
+	}
+	if err := store.CompleteTask("work", "Indexed"); err != nil {
+		t.Errorf("Expected CompleteTask to find the task, got %v", err)
+	}
+}
+
+func TestIndexLookups(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{ID: "aaaa1111", Title: "Indexed", Status: StatusTodo, Tags: []string{"Urgent"}})
+	store.SaveTask("work", &Task{ID: "bbbb2222", Title: "Other", Status: StatusTodo})
+	store.SaveTask("home", &Task{ID: "cccc3333", Title: "Plants", Status: StatusTodo, Tags: []string{"urgent"}})
+
+	// Age files and directories so they are outside the racy window
+	past := time.Now().Add(-time.Hour)
+	age := func() {
+		filepath.WalkDir(filepath.Join(store.basePath, TasksDir), func(path string, _ os.DirEntry, _ error) error {
+			return os.Chtimes(path, past, past)
+		})
+	}
+	age()
+	tasks, _ := store.LoadAllTasks()
+
+	idx := store.loadIndex()
+	if len(idx.IDs["aaaa1111"]) != 1 || len(idx.Titles["work/Indexed"]) != 1 || len(idx.Tags["urgent"]) != 2 {
+		t.Fatalf("Expected the lookup maps to be filled, got %v, %v and %v", idx.IDs, idx.Titles, idx.Tags)
+	}
+	if _, ok := store.indexed(idx, TasksDir, nil); !ok {
+		t.Fatal("Expected an unchanged tree to be served from the index")
+	}
+
+	// A file slipped in without changing its directory is not seen by an
+	// index hit, which proves the tree was not walked
+	workDir := filepath.Join(store.basePath, TasksDir, "work")
+	os.WriteFile(filepath.Join(workDir, "0000-sneaky.md"), []byte("---\nid: aaaa1111\ntitle: Sneaky\nstatus: todo\n---\n"), 0644)
+	os.Chtimes(workDir, past, past)
+	if got, err := store.GetTask("aaaa1111"); err != nil || got.Task.Title != "Indexed" {
+		t.Errorf("Expected GetTask to be served from the index, got %v, %v", got, err)
+	}
+	// A miss falls back to walking the tree
+	if err := store.CompleteTask("work", "Sneaky"); err != nil {
+		t.Errorf("Expected a miss to find the task by walking, got %v", err)
+	}
+	// Adding a file changes its directory, so the index no longer vouches
+	store.SaveTask("work", &Task{Title: "New", Status: StatusTodo})
+	if _, ok := store.indexed(store.loadIndex(), TasksDir, nil); ok {
+		t.Error("Expected a changed directory to invalidate index lookups")
+	}
+
+	// The resolver and bulk --tag read the maps of the loaded tree
+	age()
+	tasks, _ = store.LoadAllTasks()
+	for _, ref := range []string{"aaaa1111", "work/Indexed", "cccc", "home/Plants", "work/Missing"} {
+		want := findTasks(tasks, ref)
+		got, ok := store.findRef(tasks, ref)
+		if !ok || len(got) != len(want) || (len(got) > 0 && got[0] != want[0]) {
+			t.Errorf("findRef(%q) = %v, %v; want %v", ref, got, ok, want)
+		}
+	}
+	if got, ok := store.findTagged(tasks, "URGENT"); !ok || len(got) != 2 {
+		t.Errorf("Expected two tasks tagged urgent, got %v, %v", got, ok)
+	}
+	if out := runWithStore(NewBulkCmd(store), "--delete", "--tag", "urgent"); !strings.Contains(out, "complete on 2 tasks") {
+		t.Errorf("Expected bulk --tag to find both tasks, got: %s", out)
+	}
+
+	// The index is only written under the lock
+	if runtime.GOOS != "windows" {
+		os.Remove(filepath.Join(store.basePath, indexFileName))
+		store.withLock(func() error {
+			_, err := store.LoadAllTasks()
+			return err
+		})
+		if _, err := os.Stat(filepath.Join(store.basePath, indexFileName)); !os.IsNotExist(err) {
+			t.Errorf("Expected the index not to be written while the lock is busy")
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// journalFileName is the append-only operation journal in the .tada
+// directory.
+const journalFileName = "journal.jsonl"
+
+// Kinds of journal entries.
+const (
+	journalOp   = "op"
+	journalUndo = "undo"
+	journalRedo = "redo"
+)
+
+// errNothingToUndo and errNothingToRedo are returned when the journal has
+// no operation to revert or reapply.
+var (
+	errNothingToUndo = errors.New("nothing to undo")
+	errNothingToRedo = errors.New("nothing to redo")
+)
+
+// journalEntry is one line of the journal. Operations record the content of
+// every file they touched before and after; undo and redo entries point at
+// the operation they reverted or reapplied.
+type journalEntry struct {
+	// Seq is the 1-based line number, assigned when the journal is read.
+	Seq     int          `json:"-"`
+	Time    time.Time    `json:"time"`
+	Kind    string       `json:"kind"`
+	Summary string       `json:"summary,omitempty"`
+	Target  int          `json:"target,omitempty"`
+	Changes []fileChange `json:"changes,omitempty"`
+	// Undone is set by History on operations that are currently undone.
+	Undone bool `json:"-"`
+}
+
+// fileChange records one file's content around an operation. A nil Before
+// means the file was created, a nil After that it was removed. Path is
+// relative to the .tada directory.
+type fileChange struct {
+	Path   string  `json:"path"`
+	Before *string `json:"before,omitempty"`
+	After  *string `json:"after,omitempty"`
+}
+
+// journal is implemented by stores that record every change, which makes
+// changes from any process undoable later.
+type journal interface {
+	beginOperation(summary string)
+	endOperation() error
+	Undo() (*journalEntry, error)
+	Redo() (*journalEntry, error)
+	History() ([]*journalEntry, error)
+}
+
+// journaled runs fn as a single journal operation described by summary, so
+// one undo reverts everything fn changed. Stores without a journal just run
+// fn. Operations may nest; only the outermost one is recorded.
+func journaled(store Storage, summary string, fn func() error) error {
+	j, ok := store.(journal)
+	if !ok {
+		return fn()
+	}
+	j.beginOperation(summary)
+	err := fn()
+	if jerr := j.endOperation(); err == nil {
+		err = jerr
+	}
+	return err
+}
+
+func (fs *FileStore) beginOperation(summary string) {
+	fs.journalMu.Lock()
+	defer fs.journalMu.Unlock()
+	if fs.pending == nil {
+		fs.pending = &journalEntry{Kind: journalOp, Summary: summary}
+	}
+	fs.depth++
+}
+
+func (fs *FileStore) endOperation() error {
+	fs.journalMu.Lock()
+	fs.depth--
+	if fs.depth > 0 {
+		fs.journalMu.Unlock()
+		return nil
+	}
+	entry := fs.pending
+	fs.pending = nil
+	fs.journalMu.Unlock()
+
+	if len(entry.Changes) == 0 {
+		return nil
+	}
+	return fs.withLock(func() error {
+		return fs.appendJournal(entry)
+	})
+}
+
+// record journals changes made by a single store method under summary. If
+// an operation is in progress they become part of it instead. The caller
+// holds the lock.
+func (fs *FileStore) record(summary string, changes ...fileChange) {
+	fs.journalMu.Lock()
+	if fs.pending != nil {
+		fs.pending.Changes = append(fs.pending.Changes, changes...)
+		fs.journalMu.Unlock()
+		return
+	}
+	fs.journalMu.Unlock()
+
+	// The change already happened; failing to journal it must not fail it
+	if err := fs.appendJournal(&journalEntry{Kind: journalOp, Summary: summary, Changes: changes}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record change in journal: %v\n", err)
+	}
+}
+
+// change builds a fileChange for path from its content before and after.
+func (fs *FileStore) change(path string, before, after []byte) fileChange {
+	c := fileChange{Path: filepath.ToSlash(path)}
+	if key, ok := fs.indexKey(path); ok {
+		c.Path = key
+	}
+	if before != nil {
+		s := string(before)
+		c.Before = &s
+	}
+	if after != nil {
+		s := string(after)
+		c.After = &s
+	}
+	return c
+}
+
+// snapshot returns path's content, or nil if it does not exist.
+func snapshot(path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return data
+}
+
+// journalFilePath resolves a fileChange path.
+func (fs *FileStore) journalFilePath(p string) string {
+	path := filepath.FromSlash(p)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(fs.basePath, path)
+}
+
+// appendJournal writes entry as one line. The caller holds the lock.
+func (fs *FileStore) appendJournal(entry *journalEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(fs.basePath, journalFileName), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readJournal returns every entry in the journal. Lines that cannot be
+// parsed, such as one cut short by a crash, are skipped but keep their
+// sequence number.
+func (fs *FileStore) readJournal() ([]*journalEntry, error) {
+	f, err := os.Open(filepath.Join(fs.basePath, journalFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []*journalEntry
+	r := bufio.NewReader(f)
+	for seq := 1; ; seq++ {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry journalEntry
+			if json.Unmarshal(line, &entry) == nil {
+				entry.Seq = seq
+				entries = append(entries, &entry)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// replayJournal works out which operations are currently applied (done, in
+// order) and which were undone and can be redone (redo, most recent last).
+func replayJournal(entries []*journalEntry) (done, redo []*journalEntry) {
+	bySeq := make(map[int]*journalEntry)
+	for _, e := range entries {
+		switch e.Kind {
+		case journalOp:
+			bySeq[e.Seq] = e
+			done = append(done, e)
+			redo = nil
+		case journalUndo:
+			if n := len(done); n > 0 && done[n-1].Seq == e.Target {
+				redo = append(redo, done[n-1])
+				done = done[:n-1]
+			}
+		case journalRedo:
+			if n := len(redo); n > 0 && redo[n-1].Seq == e.Target {
+				done = append(done, redo[n-1])
+				redo = redo[:n-1]
+			}
+		}
+	}
+	return done, redo
+}
+
+// History returns every operation in the journal, oldest first, with
+// Undone set on those that are currently undone.
+func (fs *FileStore) History() ([]*journalEntry, error) {
+	entries, err := fs.readJournal()
+	if err != nil {
+		return nil, err
+	}
+	_, redo := replayJournal(entries)
+	undone := make(map[int]bool)
+	for _, e := range redo {
+		undone[e.Seq] = true
+	}
+	var ops []*journalEntry
+	for _, e := range entries {
+		if e.Kind == journalOp {
+			e.Undone = undone[e.Seq]
+			ops = append(ops, e)
+		}
+	}
+	return ops, nil
+}
+
+// Undo reverts the most recent operation that is still applied. It fails
+// with errConflict if any file it touched was changed since.
+func (fs *FileStore) Undo() (*journalEntry, error) {
+	var target *journalEntry
+	err := fs.withLock(func() error {
+		entries, err := fs.readJournal()
+		if err != nil {
+			return err
+		}
+		done, _ := replayJournal(entries)
+		if len(done) == 0 {
+			return errNothingToUndo
+		}
+		target = done[len(done)-1]
+		if err := fs.applyChanges(target.Changes, true); err != nil {
+			return err
+		}
+		return fs.appendJournal(&journalEntry{Kind: journalUndo, Summary: target.Summary, Target: target.Seq})
+	})
+	return target, err
+}
+
+// Redo reapplies the most recently undone operation. It fails with
+// errConflict if any file it touches was changed since the undo.
+func (fs *FileStore) Redo() (*journalEntry, error) {
+	var target *journalEntry
+	err := fs.withLock(func() error {
+		entries, err := fs.readJournal()
+		if err != nil {
+			return err
+		}
+		_, redo := replayJournal(entries)
+		if len(redo) == 0 {
+			return errNothingToRedo
+		}
+		target = redo[len(redo)-1]
+		if err := fs.applyChanges(target.Changes, false); err != nil {
+			return err
+		}
+		return fs.appendJournal(&journalEntry{Kind: journalRedo, Summary: target.Summary, Target: target.Seq})
+	})
+	return target, err
+}
+
+// applyChanges moves every file in changes from one side of the change to
+// the other: back to Before when reverting, forward to After otherwise.
+// All files are checked first, so a conflict leaves everything untouched.
+// The caller holds the lock.
+func (fs *FileStore) applyChanges(changes []fileChange, revert bool) error {
+	sides := func(c fileChange) (from, to *string) {
+		if revert {
+			return c.After, c.Before
+		}
+		return c.Before, c.After
+	}
+
+	// The same path may appear in several changes; only its first and last
+	// states matter
+	first := make(map[string]*string)
+	last := make(map[string]*string)
+	var order []string
+	for i := range changes {
+		c := changes[i]
+		if revert {
+			c = changes[len(changes)-1-i]
+		}
+		from, to := sides(c)
+		if _, seen := first[c.Path]; !seen {
+			first[c.Path] = from
+			order = append(order, c.Path)
+		}
+		last[c.Path] = to
+	}
+
+	for _, p := range order {
+		current := snapshot(fs.journalFilePath(p))
+		want := first[p]
+		if (current == nil) != (want == nil) || (want != nil && string(current) != *want) {
+			return fmt.Errorf("%w: %s", errConflict, p)
+		}
+	}
+
+	for _, p := range order {
+		path := fs.journalFilePath(p)
+		to := last[p]
+		if to == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(path, []byte(*to), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestJournalUndoRedo(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{Title: "Write report", Description: "Quarterly", Tags: []string{"q3"}, Status: StatusTodo, Priority: 2})
+	tasks, _ := store.LoadAllTasks()
+	task := tasks["work"][0]
+	added, _ := os.ReadFile(task.FilePath)
+
+	task.Task.Status = StatusInProgress
+	store.UpdateTask(task)
+	store.MoveTask(task, "home")
+	store.DeleteTask(task)
+
+	history, _ := store.History()
+	if len(history) != 4 || history[0].Summary != `add "Write report"` || history[3].Summary != `delete "Write report"` {
+		t.Fatalf("Expected four journaled operations, got %+v", history)
+	}
+
+	// Undo the delete, move and edit, in that order
+	for _, want := range []string{"delete", "move", "edit"} {
+		entry, err := store.Undo()
+		if err != nil || !strings.HasPrefix(entry.Summary, want) {
+			t.Fatalf("Expected to undo %s, got %v, %v", want, entry, err)
+		}
+	}
+	tasks, _ = store.LoadAllTasks()
+	if len(tasks["work"]) != 1 || len(tasks["home"]) != 0 {
+		t.Fatalf("Expected the task back in work, got %v", tasks)
+	}
+	if data, _ := os.ReadFile(tasks["work"][0].FilePath); string(data) != string(added) {
+		t.Errorf("Expected the file exactly as added, got:\n%s", data)
+	}
+
+	// Redo the edit; a new change then discards the remaining redos
+	if entry, err := store.Redo(); err != nil || !strings.HasPrefix(entry.Summary, "edit") {
+		t.Fatalf("Expected to redo the edit, got %v, %v", entry, err)
+	}
+	tasks, _ = store.LoadAllTasks()
+	if tasks["work"][0].Task.Status != StatusInProgress {
+		t.Errorf("Expected the redone edit, got %s", tasks["work"][0].Task.Status)
+	}
+	store.SaveTask("", &Task{Title: "Another", Status: StatusTodo})
+	if _, err := store.Redo(); !errors.Is(err, errNothingToRedo) {
+		t.Errorf("Expected nothing to redo after a new change, got %v", err)
+	}
+
+	// Undo refuses to overwrite a file changed since
+	tasks, _ = store.LoadAllTasks()
+	os.WriteFile(tasks[""][0].FilePath, []byte("---\ntitle: Another\nstatus: paused\n---\n"), 0644)
+	if _, err := store.Undo(); !errors.Is(err, errConflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+	if _, err := os.Stat(tasks[""][0].FilePath); err != nil {
+		t.Errorf("Expected the changed file to be left alone: %v", err)
+	}
+}
+
+func TestJournalGroupsOperations(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("", &Task{Title: "Water plants", Status: StatusTodo, Recur: "daily"})
+	tasks, _ := store.LoadAllTasks()
+	if _, err := completeTask(store, tasks[""][0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next instance and the archived one are reverted together
+	if entry, err := store.Undo(); err != nil || entry.Summary != `complete "Water plants"` {
+		t.Fatalf("Expected to undo the completion, got %v, %v", entry, err)
+	}
+	tasks, _ = store.LoadAllTasks()
+	archived, _ := store.LoadArchivedTasks()
+	if len(tasks[""]) != 1 || tasks[""][0].Task.Status != StatusTodo || len(archived[""]) != 0 {
+		t.Errorf("Expected only the original open task, got %d active and %d archived", len(tasks[""]), len(archived[""]))
+	}
+}
+
+func TestUndoCommands(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	if out := runWithStore(NewUndoCmd(store)); !strings.Contains(out, "Nothing to undo") {
+		t.Errorf("Expected nothing to undo, got: %s", out)
+	}
+	runWithStore(NewAddCmd(store), "Buy milk")
+	runWithStore(NewAddCmd(store), "Buy bread")
+	runWithStore(NewBulkCmd(store), "--delete", "--search", "buy")
+
+	if out := runWithStore(NewUndoCmd(store)); !strings.Contains(out, "Undone: bulk delete (2 tasks)") {
+		t.Errorf("Expected the bulk delete to be undone, got: %s", out)
+	}
+	if tasks, _ := store.LoadAllTasks(); len(tasks[""]) != 2 {
+		t.Errorf("Expected both tasks back, got %d", len(tasks[""]))
+	}
+	out := runWithStore(NewLogCmd(store))
+	if !strings.Contains(out, "bulk delete (2 tasks) (undone)") || !strings.Contains(out, `add "Buy bread"`) {
+		t.Errorf("Expected the log to list the changes, got: %s", out)
+	}
+	if out := runWithStore(NewRedoCmd(store)); !strings.Contains(out, "Redone: bulk delete") {
+		t.Errorf("Expected the bulk delete to be redone, got: %s", out)
+	}
+	if tasks, _ := store.LoadAllTasks(); len(tasks[""]) != 0 {
+		t.Errorf("Expected the tasks deleted again, got %d", len(tasks[""]))
+	}
+	if out := runWithStore(NewUndoCmd(NewMemoryStorage())); !strings.Contains(out, "not supported") {
+		t.Errorf("Expected undo to need a journal, got: %s", out)
+	}
+}
+
+func TestTUIUndoRedoUsesJournal(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	// A change made by the CLI before the TUI started
+	runWithStore(NewAddCmd(store), "From the CLI")
+
+	m := model{store: store, expanded: map[string]bool{}}
+	m2, _ := m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if tasks, _ := store.LoadAllTasks(); len(tasks[""]) != 0 || !strings.Contains(m2.(model).undoMsg, `add "From the CLI"`) {
+		t.Fatalf("Expected the TUI to undo the CLI add, got %q", m2.(model).undoMsg)
+	}
+	m3, _ := m2.(model).updateListView(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if tasks, _ := store.LoadAllTasks(); len(tasks[""]) != 1 || !strings.Contains(m3.(model).undoMsg, "Redo") {
+		t.Errorf("Expected ctrl+r to redo the add, got %q", m3.(model).undoMsg)
+	}
+}
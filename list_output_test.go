@@ -33,3 +33,15 @@ func TestListCmd_OutputFormats(t *testing.T) {
 		t.Errorf("Expected YAML output, got: %s", out.String())
 	}
 }
+
+func TestListIDsSkipsTasksWithoutID(t *testing.T) {
+	store := NewMemoryStorage()
+	store.SaveTask("", &Task{Title: "Current", Status: StatusTodo})
+	// A task from before IDs were assigned
+	store.tasks = append(store.tasks, &TaskWithPath{Task: &Task{Title: "Legacy", Status: StatusTodo}})
+
+	out := runWithStore(NewListCmd(store, nil), "--ids")
+	if lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n"); len(lines) != 1 || lines[0] != store.tasks[0].Task.ID {
+		t.Errorf("Expected only the ID of the task that has one, got %q", out)
+	}
+}
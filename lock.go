@@ -0,0 +1,75 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lockFileName is the advisory lock file in the .tada directory.
+const lockFileName = ".lock"
+
+// errConflict is returned when a task file changed on disk after it was
+// loaded, so writing the loaded copy would discard someone else's edit.
+var errConflict = errors.New("task was changed by another process since it was loaded")
+
+// withLock runs fn while holding an exclusive advisory lock on the .tada
+// directory, serializing read-modify-write operations across tada
+// processes. Locks are not reentrant, so fn must not call withLock. If the
+// lock file cannot be opened (no .tada directory yet), fn runs unlocked.
+func (fs *FileStore) withLock(fn func() error) error {
+	f, err := os.OpenFile(filepath.Join(fs.basePath, lockFileName), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fn()
+	}
+	defer f.Close()
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", fs.basePath, err)
+	}
+	defer unlockFile(f)
+	return fn()
+}
+
+// tryWithLock runs fn while holding the lock if it can be taken without
+// waiting, and reports whether fn ran. Since locks are not reentrant, it
+// never runs fn inside another locked operation of this store.
+func (fs *FileStore) tryWithLock(fn func() error) (bool, error) {
+	f, err := os.OpenFile(filepath.Join(fs.basePath, lockFileName), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return false, nil
+	}
+	defer f.Close()
+	if !tryLockFile(f) {
+		return false, nil
+	}
+	defer unlockFile(f)
+	return true, fn()
+}
+
+// contentHash fingerprints a task file's content.
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// checkUnchanged returns errConflict if t's file no longer has the content
+// it was loaded with. Tasks that were not loaded from disk are not checked.
+func checkUnchanged(t *TaskWithPath) error {
+	if t.Hash == "" {
+		return nil
+	}
+	data, err := os.ReadFile(t.FilePath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s was removed", errConflict, t.Task.Title)
+	}
+	if err != nil {
+		return err
+	}
+	if contentHash(data) != t.Hash {
+		return fmt.Errorf("%w: %s", errConflict, t.Task.Title)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileStore_RefusesStaleWrites(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("", &Task{Title: "Shared", Status: StatusTodo, Priority: 3})
+
+	mine, _ := store.LoadAllTasks()
+	theirs, _ := store.LoadAllTasks()
+
+	// Another process edits the task after we loaded it
+	theirs[""][0].Task.Priority = 1
+	if err := store.UpdateTask(theirs[""][0]); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := mine[""][0]
+	stale.Task.Status = StatusInProgress
+	if err := store.UpdateTask(stale); !errors.Is(err, errConflict) {
+		t.Fatalf("Expected a conflict, got %v", err)
+	}
+	if err := store.ArchiveTask(stale); !errors.Is(err, errConflict) {
+		t.Errorf("Expected archiving a stale task to conflict, got %v", err)
+	}
+	if err := store.DeleteTask(stale); !errors.Is(err, errConflict) {
+		t.Errorf("Expected deleting a stale task to conflict, got %v", err)
+	}
+	data, _ := os.ReadFile(stale.FilePath)
+	if !strings.Contains(string(data), "priority: 1") || !strings.Contains(string(data), "status: todo") {
+		t.Errorf("Expected the other edit to survive, got:\n%s", data)
+	}
+
+	// Our own successive writes keep the fingerprint current
+	fresh, _ := store.LoadAllTasks()
+	task := fresh[""][0]
+	task.Task.Status = StatusInProgress
+	if err := store.UpdateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	task.Task.Status = StatusPaused
+	if err := store.UpdateTask(task); err != nil {
+		t.Errorf("Expected a second write of our own copy to succeed, got %v", err)
+	}
+
+	// Deleting and restoring carry the fingerprint along, which TUI undo relies on
+	if err := store.DeleteTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RestoreTrashedTask(task); err != nil {
+		t.Errorf("Expected a deleted task to be restored, got %v", err)
+	}
+}
+
+func TestFileStore_WithLockSerializes(t *testing.T) {
+	dir := t.TempDir()
+	first, second := NewFileStore(dir), NewFileStore(dir)
+
+	release := make(chan struct{})
+	held := make(chan struct{})
+	go first.withLock(func() error {
+		close(held)
+		<-release
+		return nil
+	})
+	<-held
+
+	acquired := make(chan struct{})
+	go func() {
+		second.withLock(func() error { return nil })
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Expected the second lock to wait for the first")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-acquired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the second lock to be acquired after release")
+	}
+}
+
+func TestTUICycleStatusConflict(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("", &Task{Title: "Shared", Status: StatusTodo, Priority: 3})
+	mine, _ := store.LoadAllTasks()
+	theirs, _ := store.LoadAllTasks()
+	theirs[""][0].Task.Title = "Renamed elsewhere"
+	store.UpdateTask(theirs[""][0])
+
+	m := model{tasks: mine, store: store, expanded: map[string]bool{}}
+	task := mine[""][0]
+	m.cycleTaskStatus(task, 1)
+	if task.Task.Status != StatusTodo || !strings.Contains(m.undoMsg, "Not saved") {
+		t.Errorf("Expected the stale status change to be refused, got %s / %q", task.Task.Status, m.undoMsg)
+	}
+}
+
+func TestFileStore_FailedArchiveLeavesTask(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("", &Task{Title: "Shared", Status: StatusTodo})
+
+	mine, _ := store.LoadAllTasks()
+	theirs, _ := store.LoadAllTasks()
+	theirs[""][0].Task.Priority = 1
+	store.UpdateTask(theirs[""][0])
+
+	stale := mine[""][0]
+	history := len(stale.Task.History)
+	if err := store.ArchiveTask(stale); !errors.Is(err, errConflict) {
+		t.Fatalf("Expected a conflict, got %v", err)
+	}
+	if stale.Task.Status != StatusTodo || stale.Task.CompletedAt != nil || len(stale.Task.History) != history {
+		t.Errorf("Expected the failed archive to leave the task as it was, got %+v", stale.Task)
+	}
+
+	// Restoring likewise changes the task only once it is restored
+	if err := store.ArchiveTask(theirs[""][0]); err != nil {
+		t.Fatal(err)
+	}
+	archived, _ := store.LoadArchivedTasks()
+	ours, other := archived[""][0], archived[""][0].clone()
+	other.Task.Priority = 2
+	store.UpdateTask(other)
+	if err := store.RestoreTask(ours); !errors.Is(err, errConflict) {
+		t.Fatalf("Expected a conflict, got %v", err)
+	}
+	if ours.Task.Status != StatusDone || ours.Task.CompletedAt == nil {
+		t.Errorf("Expected the failed restore to leave the task as it was, got %+v", ours.Task)
+	}
+	if err := store.RestoreTask(other); err != nil || other.Task.Status != StatusTodo {
+		t.Errorf("Expected the current copy to be restored as todo, got %v, %s", err, other.Task.Status)
+	}
+}
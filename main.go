@@ -56,20 +56,31 @@ func main() {
 	}
 	store := NewFileStore(tadaDir)
 	cfg, _ := loadConfig()
+	if err := setWorkflow(cfg.Statuses); err != nil {
+		styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Invalid statuses in config, using the defaults: %v", err))
+		fmt.Fprintln(os.Stderr, styledErr)
+	}
+	if err := setFields(cfg.Fields); err != nil {
+		styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Invalid fields in config, ignoring them: %v", err))
+		fmt.Fprintln(os.Stderr, styledErr)
+	}
 	var rootCmd = &cobra.Command{
 		Use:   "tada",
 		Short: "A terminal-based todo application",
 		Long:  "A terminal-based todo application\n\nTada is a simple yet powerful todo application with both CLI and TUI interfaces",
 		Run: func(cmd *cobra.Command, args []string) {
-			RunTUIWithConfig(cfg)
+			RunTUIWithConfig(store, cfg)
 		},
 	}
 
 	rootCmd.CompletionOptions.DisableDefaultCmd = false
 
-	rootCmd.AddCommand(NewAddCmd(store), NewListCmd(store, cfg), NewCompleteCmd(store), NewTuiCmd(store), NewEditCmd(store), NewDeleteCmd(store), NewShowCmd(store), NewMoveCmd(store), NewCopyCmd(store), NewBulkCmd(store), NewConfigCmd(), NewVersionCmd(), NewExportCmd(store))
+	rootCmd.AddCommand(NewAddCmd(store), NewListCmd(store, cfg), NewCompleteCmd(store), NewTuiCmd(store), NewEditCmd(store), NewDeleteCmd(store), NewShowCmd(store), NewMoveCmd(store), NewCopyCmd(store), NewBulkCmd(store), NewConfigCmd(), NewVersionCmd(), NewExportCmd(store), NewSubCmd(store), NewArchiveCmd(store, cfg), NewDoctorCmd(store), NewTrashCmd(store, cfg), NewUndoCmd(store), NewRedoCmd(store), NewLogCmd(store), NewStartCmd(store), NewStopCmd(store), NewTimesheetCmd(store), NewViewCmd(store, cfg))
 
-	if err := fang.Execute(context.TODO(), rootCmd); err != nil {
-		osExit(1)
+	code := execute(rootCmd, func(root *cobra.Command) error {
+		return fang.Execute(context.TODO(), root)
+	})
+	if code != 0 {
+		osExit(code)
 	}
 }
@@ -1,14 +1,18 @@
 package main
 
 import (
+	"fmt"
 	"sync"
 	"time"
 )
 
 // MemoryStorage is an in-memory implementation of Storage for testing.
 type MemoryStorage struct {
-	mu    sync.Mutex
-	tasks []*TaskWithPath
+	mu       sync.Mutex
+	tasks    []*TaskWithPath
+	archived []*TaskWithPath
+	trashed  []*TaskWithPath
+	nextID   int
 }
 
 func NewMemoryStorage() *MemoryStorage {
@@ -26,12 +30,206 @@ func (m *MemoryStorage) LoadAllTasks() (map[string][]*TaskWithPath, error) {
 	return result, nil
 }
 
+func (m *MemoryStorage) LoadArchivedTasks() (map[string][]*TaskWithPath, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[string][]*TaskWithPath)
+	for _, t := range m.archived {
+		result[t.Topic] = append(result[t.Topic], t)
+	}
+	return result, nil
+}
+
+func (m *MemoryStorage) LoadTrashedTasks() (map[string][]*TaskWithPath, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[string][]*TaskWithPath)
+	for _, t := range m.trashed {
+		result[t.Topic] = append(result[t.Topic], t)
+	}
+	return result, nil
+}
+
 func (m *MemoryStorage) AddTask(task Task) error {
+	return m.SaveTask("", &task)
+}
+
+// newID must be called with m.mu held.
+func (m *MemoryStorage) newID() string {
+	m.nextID++
+	return fmt.Sprintf("%0*x", idLength, m.nextID)
+}
+
+// indexOf finds t in list by identity or ID.
+func indexOf(list []*TaskWithPath, t *TaskWithPath) int {
+	for i, existing := range list {
+		if existing == t || (t.Task != nil && existing.Task.ID != "" && existing.Task.ID == t.Task.ID) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *MemoryStorage) GetTask(id string) (*TaskWithPath, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.tasks {
+		if t.Task.ID == id {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", errTaskNotFound, id)
+}
+
+func (m *MemoryStorage) SaveTask(topic string, task *Task) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if task.CreatedAt.IsZero() {
 		task.CreatedAt = time.Now()
 	}
-	m.tasks = append(m.tasks, &TaskWithPath{Task: &task, Topic: "", FilePath: ""})
+	if task.ID == "" {
+		task.ID = m.newID()
+	}
+	m.tasks = append(m.tasks, &TaskWithPath{Task: task, Topic: topic, FilePath: ""})
+	return nil
+}
+
+func (m *MemoryStorage) UpdateTask(t *TaskWithPath) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := indexOf(m.tasks, t)
+	if i < 0 {
+		m.tasks = append(m.tasks, t)
+		return nil
+	}
+	m.tasks[i] = t
+	return nil
+}
+
+func (m *MemoryStorage) UpdateTasks(fn func(tasks map[string][]*TaskWithPath) ([]*TaskWithPath, error)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tasks := make(map[string][]*TaskWithPath)
+	for _, t := range m.tasks {
+		tasks[t.Topic] = append(tasks[t.Topic], t)
+	}
+	changed, err := fn(tasks)
+	if err != nil {
+		return err
+	}
+	for _, t := range changed {
+		if i := indexOf(m.tasks, t); i >= 0 {
+			m.tasks[i] = t
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) DeleteTask(t *TaskWithPath) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := indexOf(m.tasks, t)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", errTaskNotFound, t.Task.Title)
+	}
+	now := time.Now()
+	t.Task.stopTimer(now)
+	t.Task.DeletedAt = &now
+	m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+	m.trashed = append(m.trashed, t)
+	return nil
+}
+
+func (m *MemoryStorage) RestoreTrashedTask(t *TaskWithPath) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := indexOf(m.trashed, t)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", errTaskNotFound, t.Task.Title)
+	}
+	t.Task.DeletedAt = nil
+	m.trashed = append(m.trashed[:i], m.trashed[i+1:]...)
+	m.tasks = append(m.tasks, t)
+	return nil
+}
+
+func (m *MemoryStorage) PurgeTask(t *TaskWithPath) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := indexOf(m.trashed, t)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", errTaskNotFound, t.Task.Title)
+	}
+	m.trashed = append(m.trashed[:i], m.trashed[i+1:]...)
+	return nil
+}
+
+func (m *MemoryStorage) MoveTask(t *TaskWithPath, topic string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if indexOf(m.tasks, t) < 0 {
+		return fmt.Errorf("%w: %s", errTaskNotFound, t.Task.Title)
+	}
+	t.Topic = topic
+	return nil
+}
+
+func (m *MemoryStorage) CopyTask(t *TaskWithPath, topic string) (*TaskWithPath, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	task := *t.Task
+	task.resetForCopy(m.newID(), time.Now())
+	copied := &TaskWithPath{Task: &task, Topic: topic}
+	m.tasks = append(m.tasks, copied)
+	return copied, nil
+}
+
+func (m *MemoryStorage) CompleteTask(topic, title string) error {
+	m.mu.Lock()
+	var found *TaskWithPath
+	for _, t := range m.tasks {
+		if t.Topic == topic && t.Task.Title == title {
+			found = t
+			break
+		}
+	}
+	m.mu.Unlock()
+	if found == nil {
+		return fmt.Errorf("task not found: %s", title)
+	}
+	_, err := completeTask(m, found)
+	return err
+}
+
+func (m *MemoryStorage) ArchiveTask(t *TaskWithPath) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := indexOf(m.tasks, t)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", errTaskNotFound, t.Task.Title)
+	}
+	if t.Task.Status != StatusCancelled {
+		t.Task.setStatus(StatusDone)
+	}
+	if t.Task.CompletedAt == nil {
+		now := time.Now()
+		t.Task.CompletedAt = &now
+	}
+	m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+	m.archived = append(m.archived, t)
+	return nil
+}
+
+func (m *MemoryStorage) RestoreTask(t *TaskWithPath) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := indexOf(m.archived, t)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", errTaskNotFound, t.Task.Title)
+	}
+	t.Task.setStatus(StatusTodo)
+	t.Task.CompletedAt = nil
+	m.archived = append(m.archived[:i], m.archived[i+1:]...)
+	m.tasks = append(m.tasks, t)
 	return nil
 }
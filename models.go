@@ -1,6 +1,7 @@
 package main
 
 import (
+	"slices"
 	"time"
 )
 
@@ -15,22 +16,176 @@ const (
 )
 
 type Task struct {
+	ID          string     `yaml:"id,omitempty"`
 	Title       string     `yaml:"title"`
 	Description string     `yaml:"description,omitempty"`
 	Priority    int        `yaml:"priority,omitempty"`
 	Status      TaskStatus `yaml:"status"`
 	Tags        []string   `yaml:"tags,omitempty"`
 	CreatedAt   time.Time  `yaml:"created_at"`
+	// UpdatedAt is set whenever tada writes the task file.
+	UpdatedAt   *time.Time `yaml:"updated_at,omitempty"`
 	CompletedAt *time.Time `yaml:"completed_at,omitempty"`
+	// DeletedAt and DeletedFrom are set on tasks in the trash. DeletedFrom
+	// is the file the task was deleted from, relative to the .tada directory.
+	DeletedAt   *time.Time `yaml:"deleted_at,omitempty"`
+	DeletedFrom string     `yaml:"deleted_from,omitempty"`
+	Due         *time.Time `yaml:"due,omitempty"`
+	Scheduled   *time.Time `yaml:"scheduled,omitempty"`
+	Recur       string     `yaml:"recur,omitempty"`
+	DependsOn   []string   `yaml:"depends_on,omitempty"`
+	// Fields holds the values of custom fields declared in the config.
+	Fields map[string]string `yaml:"fields,omitempty"`
+	// History lists every status the task went through, oldest first.
+	History []StatusChange `yaml:"history,omitempty"`
+	// TimeLog holds the intervals tracked with tada start and tada stop. At
+	// most one entry in the workspace is open, with no End.
+	TimeLog []TimeEntry `yaml:"time_log,omitempty"`
+	// Body is the Markdown after the frontmatter, kept byte-for-byte.
+	Body string `yaml:"-"`
+	// Frontmatter is the YAML the task was read from. Writing the task
+	// updates it in place, keeping keys of other tools, comments and order.
+	Frontmatter string `yaml:"-" json:"-"`
+}
+
+// StatusChange is one status transition in a task's history. The first
+// entry has no From and records the status the task was created with.
+type StatusChange struct {
+	From TaskStatus `yaml:"from,omitempty"`
+	To   TaskStatus `yaml:"to"`
+	At   time.Time  `yaml:"at"`
+}
+
+// TimeEntry is one tracked interval of work on a task. End is nil while
+// the timer is running.
+type TimeEntry struct {
+	Start time.Time  `yaml:"start"`
+	End   *time.Time `yaml:"end,omitempty"`
+}
+
+// setStatus changes the task's status, recording the transition in its
+// history. Setting the current status again records nothing. A running
+// timer stops unless the new status keeps it.
+func (t *Task) setStatus(status TaskStatus) {
+	if status == t.Status {
+		return
+	}
+	now := time.Now()
+	t.History = append(t.History, StatusChange{From: t.Status, To: status, At: now})
+	t.Status = status
+	if !status.keepsTimer() {
+		t.stopTimer(now)
+	}
+}
+
+// resetForCopy turns t into a new copy of itself with the given ID, created
+// at now. The copy starts its own status history and keeps only finished
+// time entries.
+func (t *Task) resetForCopy(id string, now time.Time) {
+	t.ID = id
+	t.CreatedAt = now
+	t.UpdatedAt = nil
+	t.History = []StatusChange{{To: t.Status, At: now}}
+	var timeLog []TimeEntry
+	for _, e := range t.TimeLog {
+		if e.End != nil {
+			timeLog = append(timeLog, e)
+		}
+	}
+	t.TimeLog = timeLog
+}
+
+// isValid reports whether s is a status of the active workflow.
+func (s TaskStatus) isValid() bool {
+	_, ok := statusDef(s)
+	return ok
+}
+
+// isClosed reports whether a status ends a task's life.
+func (s TaskStatus) isClosed() bool {
+	d, ok := statusDef(s)
+	return ok && d.Closed
+}
+
+// isOverdue reports whether an open task's due day has passed.
+func (t *Task) isOverdue(now time.Time) bool {
+	if t.Due == nil || t.Status.isClosed() {
+		return false
+	}
+	if t.Due.Equal(startOfDay(*t.Due)) {
+		return t.Due.Before(startOfDay(now))
+	}
+	return t.Due.Before(now)
+}
+
+// hasGeneratedBody reports whether the body is empty or still exactly what
+// tada generates from the title and description, so it can be regenerated
+// when those change without losing anything written by hand.
+func (t *Task) hasGeneratedBody() bool {
+	return t.Body == "" || t.Body == defaultTaskBody(t)
 }
 
 type TaskWithPath struct {
 	Task     *Task
 	FilePath string
 	Topic    string
+	// Hash fingerprints the file content as loaded or last written, so
+	// FileStore can refuse to overwrite changes made by another process.
+	Hash string `json:"-" yaml:"-"`
+}
+
+// clone copies t for changes that must not show until they are saved. The
+// status history and time log are copied too, since status changes append
+// to one and stop timers in the other.
+func (t *TaskWithPath) clone() *TaskWithPath {
+	task := *t.Task
+	task.History = slices.Clone(task.History)
+	task.TimeLog = slices.Clone(task.TimeLog)
+	copied := *t
+	copied.Task = &task
+	return &copied
+}
+
+// adopt takes over the saved state of changed, a clone of t.
+func (t *TaskWithPath) adopt(changed *TaskWithPath) {
+	*t.Task = *changed.Task
+	t.FilePath, t.Topic, t.Hash = changed.FilePath, changed.Topic, changed.Hash
 }
 
-// Storage is a minimal interface for testable task storage.
+// Storage is the task persistence interface used by every command and the
+// TUI. FileStore is the on-disk implementation.
 type Storage interface {
+	// LoadAllTasks returns all active tasks grouped by topic.
 	LoadAllTasks() (map[string][]*TaskWithPath, error)
+	// LoadArchivedTasks returns all archived tasks grouped by topic.
+	LoadArchivedTasks() (map[string][]*TaskWithPath, error)
+	// GetTask returns the active task with the given ID.
+	GetTask(id string) (*TaskWithPath, error)
+	// SaveTask creates a new task under topic.
+	SaveTask(topic string, task *Task) error
+	// UpdateTask writes changes to an existing task in place.
+	UpdateTask(t *TaskWithPath) error
+	// DeleteTask moves a task into the trash.
+	DeleteTask(t *TaskWithPath) error
+	// LoadTrashedTasks returns all deleted tasks grouped by topic.
+	LoadTrashedTasks() (map[string][]*TaskWithPath, error)
+	// RestoreTrashedTask moves a deleted task back to where it was deleted
+	// from.
+	RestoreTrashedTask(t *TaskWithPath) error
+	// PurgeTask permanently removes a deleted task.
+	PurgeTask(t *TaskWithPath) error
+	// MoveTask moves a task to another topic, updating t.
+	MoveTask(t *TaskWithPath, topic string) error
+	// CopyTask duplicates a task into topic under a new ID.
+	CopyTask(t *TaskWithPath, topic string) (*TaskWithPath, error)
+	// CompleteTask marks the task with the given topic and title done and archives it.
+	CompleteTask(topic, title string) error
+	// ArchiveTask marks an open task done and moves it into the archive.
+	ArchiveTask(t *TaskWithPath) error
+	// RestoreTask moves an archived task back into the active tasks as todo.
+	RestoreTask(t *TaskWithPath) error
+	// UpdateTasks calls fn with the active tasks and writes the tasks it
+	// returns. No other change is made in between, so what fn checked
+	// still holds when its changes are written.
+	UpdateTasks(fn func(tasks map[string][]*TaskWithPath) ([]*TaskWithPath, error)) error
 }
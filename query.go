@@ -0,0 +1,372 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// taskQuery is a parsed filter such as
+//
+//	status:todo tag:urgent priority<=2 topic:work/* created<7d -tag:someday "free text"
+//
+// shared by list, bulk, export, stats and the TUI search bar. A task
+// matches when it satisfies every term.
+type taskQuery struct {
+	terms []queryTerm
+	// fuzzy matches free text fuzzily, as tada list --fuzzy does.
+	fuzzy bool
+}
+
+// queryTerm is one condition of a query; a leading - negates it.
+type queryTerm struct {
+	negate bool
+	// text is the lowercased free text of a term without a key, matched
+	// against the title, description, tags and topic.
+	text  string
+	match func(t *TaskWithPath, byID map[string]*TaskWithPath) bool
+}
+
+// queryKeyPattern matches the key and operator at the start of a term.
+var queryKeyPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*)(<=|>=|<|>|:|=)`)
+
+// queryKeys are the built-in keys, listed in parse errors along with the
+// custom fields.
+var queryKeys = []string{"status", "tag", "topic", "title", "id", "priority", "due", "scheduled", "created", "updated", "completed", "is"}
+
+// parseQuery parses a query. Terms are separated by spaces; double quotes
+// group words into one term, as in "free text" or title:"fix tests".
+func parseQuery(input string, now time.Time) (*taskQuery, error) {
+	tokens, err := splitQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	q := &taskQuery{}
+	for _, tok := range tokens {
+		text, quoted := tok.text, tok.quoted
+		negate := false
+		if len(text) > 1 && text[0] == '-' && quoted != 0 {
+			negate = true
+			text = text[1:]
+			if quoted > 0 {
+				quoted--
+			}
+		}
+		// Only an unquoted key counts, so "a:b" is free text
+		head := text
+		if quoted >= 0 {
+			head = text[:quoted]
+		}
+		m := queryKeyPattern.FindStringSubmatch(head)
+		if m == nil {
+			q.terms = append(q.terms, queryTerm{negate: negate, text: strings.ToLower(text)})
+			continue
+		}
+		match, err := newQueryMatch(strings.ToLower(m[1]), m[2], text[len(m[0]):], now)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", tok.text, err)
+		}
+		q.terms = append(q.terms, queryTerm{negate: negate, match: match})
+	}
+	return q, nil
+}
+
+// queryToken is a term of the query text with its quotes removed. quoted
+// is where the first quoted part began, or -1.
+type queryToken struct {
+	text   string
+	quoted int
+}
+
+// splitQuery splits a query into terms at spaces outside double quotes.
+func splitQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	var cur strings.Builder
+	inQuote, started, quoted := false, false, -1
+	for _, r := range input {
+		switch {
+		case r == '"':
+			if quoted < 0 {
+				quoted = cur.Len()
+			}
+			inQuote = !inQuote
+			started = true
+		case unicode.IsSpace(r) && !inQuote:
+			if started {
+				tokens = append(tokens, queryToken{cur.String(), quoted})
+				cur.Reset()
+				started, quoted = false, -1
+			}
+		default:
+			cur.WriteRune(r)
+			started = true
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote in %q", input)
+	}
+	if started {
+		tokens = append(tokens, queryToken{cur.String(), quoted})
+	}
+	return tokens, nil
+}
+
+// add appends the term key op value, as the filter flags that are
+// shorthands for query terms do.
+func (q *taskQuery) add(key, op, value string, now time.Time) error {
+	match, err := newQueryMatch(key, op, value, now)
+	if err != nil {
+		return err
+	}
+	q.terms = append(q.terms, queryTerm{match: match})
+	return nil
+}
+
+// matches reports whether t satisfies every term. byID resolves
+// dependencies for is:ready and is:blocked.
+func (q *taskQuery) matches(t *TaskWithPath, byID map[string]*TaskWithPath) bool {
+	for _, term := range q.terms {
+		var ok bool
+		if term.match != nil {
+			ok = term.match(t, byID)
+		} else {
+			ok = q.matchText(t, term.text)
+		}
+		if ok == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matchText reports whether the title, description, tags or topic of t
+// contain text.
+func (q *taskQuery) matchText(t *TaskWithPath, text string) bool {
+	for _, s := range []string{t.Task.Title, t.Task.Description, strings.Join(t.Task.Tags, ","), t.Topic} {
+		s = strings.ToLower(s)
+		if q.fuzzy && fuzzy.MatchFold(text, s) || !q.fuzzy && strings.Contains(s, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns the tasks that match q. Dependencies are resolved against
+// all of tasks.
+func (q *taskQuery) filter(tasks map[string][]*TaskWithPath) map[string][]*TaskWithPath {
+	if len(q.terms) == 0 {
+		return tasks
+	}
+	byID := indexByID(tasks)
+	filtered := make(map[string][]*TaskWithPath)
+	for topic, taskList := range tasks {
+		for _, t := range taskList {
+			if q.matches(t, byID) {
+				filtered[topic] = append(filtered[topic], t)
+			}
+		}
+	}
+	return filtered
+}
+
+// newQueryMatch builds the condition for key op value, where op is one of
+// : = < <= > >=.
+func newQueryMatch(key, op, value string, now time.Time) (func(*TaskWithPath, map[string]*TaskWithPath) bool, error) {
+	if op == "=" {
+		op = ":"
+	}
+	switch key {
+	case "status", "tag", "topic", "title":
+		if op != ":" {
+			return nil, fmt.Errorf("%s only supports %s:value", key, key)
+		}
+		pattern := globPattern(value)
+		return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool {
+			switch key {
+			case "status":
+				return pattern.MatchString(string(t.Task.Status))
+			case "topic":
+				return pattern.MatchString(t.Topic)
+			case "title":
+				return pattern.MatchString(t.Task.Title)
+			}
+			for _, tag := range t.Task.Tags {
+				if pattern.MatchString(tag) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case "id":
+		if op != ":" {
+			return nil, fmt.Errorf("id only supports id:prefix")
+		}
+		prefix := strings.ToLower(value)
+		return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool {
+			return t.Task.ID != "" && strings.HasPrefix(strings.ToLower(t.Task.ID), prefix)
+		}, nil
+	case "priority":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("priority must be a whole number, not %q", value)
+		}
+		return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool {
+			return compareResult(op, t.Task.Priority-n)
+		}, nil
+	case "due":
+		return dateMatch(op, value, now, false, func(t *Task) *time.Time { return t.Due })
+	case "scheduled":
+		return dateMatch(op, value, now, false, func(t *Task) *time.Time { return t.Scheduled })
+	case "created":
+		return dateMatch(op, value, now, true, func(t *Task) *time.Time { return &t.CreatedAt })
+	case "updated":
+		return dateMatch(op, value, now, true, func(t *Task) *time.Time { return t.UpdatedAt })
+	case "completed":
+		return dateMatch(op, value, now, true, func(t *Task) *time.Time { return t.CompletedAt })
+	case "is":
+		return stateMatch(op, value, now)
+	}
+
+	f, ok := fieldDef(key)
+	if !ok {
+		keys := append([]string(nil), queryKeys...)
+		for _, f := range customFields {
+			keys = append(keys, f.Name)
+		}
+		return nil, fmt.Errorf("unknown filter %q (use %s, or quote text containing %s)", key, strings.Join(keys, ", "), op)
+	}
+	return fieldMatch(f, op, value, now)
+}
+
+// globPattern compiles a case-insensitive pattern in which * matches any
+// run of characters, slashes included.
+func globPattern(glob string) *regexp.Regexp {
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(glob), `\*`, ".*")
+	return regexp.MustCompile("(?i)^" + quoted + "$")
+}
+
+// compareResult applies op to the sign of a comparison.
+func compareResult(op string, c int) bool {
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return c == 0
+}
+
+// dateMatch compares a date of the task with value. value is a date as
+// accepted by --due ("none" for no date) and : matches the whole day. For
+// dates in the past, value may also be an age: created<7d matches tasks
+// created less than 7 days ago.
+func dateMatch(op, value string, now time.Time, past bool, get func(*Task) *time.Time) (func(*TaskWithPath, map[string]*TaskWithPath) bool, error) {
+	if strings.EqualFold(value, "none") {
+		if op != ":" {
+			return nil, fmt.Errorf("none only works with :")
+		}
+		return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool { return get(t.Task) == nil }, nil
+	}
+	if past {
+		if cutoff, err := parseAge(value, now); err == nil {
+			if op == ":" {
+				op = "<="
+			}
+			return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool {
+				d := get(t.Task)
+				// A younger task has a later date
+				return d != nil && compareResult(op, cutoff.Compare(*d))
+			}, nil
+		}
+	}
+	day, err := parseDate(value, now)
+	if err != nil {
+		return nil, err
+	}
+	start := startOfDay(day)
+	end := start.AddDate(0, 0, 1)
+	return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool {
+		d := get(t.Task)
+		if d == nil {
+			return false
+		}
+		switch op {
+		case "<":
+			return d.Before(day)
+		case "<=":
+			return d.Before(end)
+		case ">":
+			return d.After(day)
+		case ">=":
+			return !d.Before(start)
+		}
+		return !d.Before(start) && d.Before(end)
+	}, nil
+}
+
+// stateMatch handles is:open, is:closed, is:overdue, is:ready and
+// is:blocked.
+func stateMatch(op, value string, now time.Time) (func(*TaskWithPath, map[string]*TaskWithPath) bool, error) {
+	if op != ":" {
+		return nil, fmt.Errorf("is only supports is:state")
+	}
+	switch strings.ToLower(value) {
+	case "open":
+		return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool { return !t.Task.Status.isClosed() }, nil
+	case "closed":
+		return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool { return t.Task.Status.isClosed() }, nil
+	case "overdue":
+		return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool { return t.Task.isOverdue(now) }, nil
+	case "ready":
+		return func(t *TaskWithPath, byID map[string]*TaskWithPath) bool { return isReady(t.Task, byID) }, nil
+	case "blocked":
+		return func(t *TaskWithPath, byID map[string]*TaskWithPath) bool {
+			return len(openDependencies(t.Task, byID)) > 0
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown state %q (use open, closed, overdue, ready or blocked)", value)
+}
+
+// fieldMatch compares a custom field with value, normalized for the field's
+// type. An empty value matches tasks without the field; string and url
+// fields match like tag:, with * as a wildcard.
+func fieldMatch(f FieldDef, op, value string, now time.Time) (func(*TaskWithPath, map[string]*TaskWithPath) bool, error) {
+	if value == "" {
+		if op != ":" {
+			return nil, fmt.Errorf("%s: a comparison needs a value", f.Name)
+		}
+		return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool { return t.Task.Fields[f.Name] == "" }, nil
+	}
+	if op == ":" && (f.Type == "string" || f.Type == "url") {
+		pattern := globPattern(value)
+		return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool {
+			return pattern.MatchString(t.Task.Fields[f.Name])
+		}, nil
+	}
+	want, err := f.normalize(value, now)
+	if err != nil {
+		return nil, err
+	}
+	return func(t *TaskWithPath, _ map[string]*TaskWithPath) bool {
+		got := t.Task.Fields[f.Name]
+		if got == "" {
+			return false
+		}
+		c := 0
+		if f.less(got, want) {
+			c = -1
+		} else if f.less(want, got) {
+			c = 1
+		}
+		return compareResult(op, c)
+	}, nil
+}
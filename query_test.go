@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseQuery(t *testing.T) {
+	if err := setFields([]FieldDef{{Name: "severity", Type: "enum", Values: []string{"low", "high"}}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { setFields(nil) })
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+	tasks := map[string][]*TaskWithPath{
+		"work/backend": {
+			{Topic: "work/backend", Task: &Task{ID: "aaaa1111", Title: "Deploy api", Status: StatusTodo, Priority: 1, Tags: []string{"urgent"}, CreatedAt: now.AddDate(0, 0, -2), Due: &due, Fields: map[string]string{"severity": "high"}}},
+			{Topic: "work/backend", Task: &Task{ID: "bbbb2222", Title: "Write tests", Status: StatusInProgress, Priority: 3, Tags: []string{"someday"}, CreatedAt: now.AddDate(0, 0, -30), DependsOn: []string{"aaaa1111"}}},
+		},
+		"home": {
+			{Topic: "home", Task: &Task{ID: "cccc3333", Title: "Water plants", Description: "the ferns", Status: StatusDone, CreatedAt: now.AddDate(0, 0, -10)}},
+		},
+	}
+
+	for _, tc := range []struct {
+		query string
+		want  string
+	}{
+		{"", "Deploy api, Water plants, Write tests"},
+		{"status:todo", "Deploy api"},
+		{"tag:URGENT", "Deploy api"},
+		{"-tag:someday", "Deploy api, Water plants"},
+		{"priority<=2", "Deploy api, Water plants"},
+		{"priority>2", "Write tests"},
+		{"topic:work/*", "Deploy api, Write tests"},
+		{"topic:work", ""},
+		{"created<7d", "Deploy api"},
+		{"created>7d", "Water plants, Write tests"},
+		{"created>=2026-10-07", "Deploy api, Water plants"},
+		{"due:2026-10-20", "Deploy api"},
+		{"due<3d", ""},
+		{"due<=3d", "Deploy api"},
+		{"due:none", "Water plants, Write tests"},
+		{"is:blocked", "Write tests"},
+		{"is:open -is:blocked", "Deploy api"},
+		{"id:BBBB", "Write tests"},
+		{"severity:HIGH", "Deploy api"},
+		{"severity:", "Water plants, Write tests"},
+		{"ferns", "Water plants"},
+		{`"deploy api"`, "Deploy api"},
+		{`title:"write *"`, "Write tests"},
+		{`-"write tests" work`, "Deploy api"},
+		{"status:todo tag:urgent priority<=2 topic:work/* created<7d -tag:someday deploy", "Deploy api"},
+	} {
+		q, err := parseQuery(tc.query, now)
+		if err != nil {
+			t.Errorf("parseQuery(%q): %v", tc.query, err)
+			continue
+		}
+		var titles []string
+		for _, list := range q.filter(tasks) {
+			for _, task := range list {
+				titles = append(titles, task.Task.Title)
+			}
+		}
+		sort.Strings(titles)
+		if got := strings.Join(titles, ", "); got != tc.want {
+			t.Errorf("%q matched %q, want %q", tc.query, got, tc.want)
+		}
+	}
+
+	for query, msg := range map[string]string{
+		"stauts:todo":     `unknown filter "stauts" (use status, tag,`,
+		"priority<=high":  `"priority<=high": priority must be a whole number`,
+		"status<todo":     "status only supports status:value",
+		"due:someday":     `unrecognised date "someday"`,
+		"is:sleepy":       `unknown state "sleepy"`,
+		"severity:urgent": `"urgent" is not one of low, high`,
+		`title:"open`:     "unterminated quote",
+	} {
+		if _, err := parseQuery(query, now); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("parseQuery(%q): expected an error containing %q, got %v", query, msg, err)
+		}
+	}
+}
+
+func TestQueryInCommands(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{ID: "11111111", Title: "Deploy api", Status: StatusTodo, Priority: 1, Tags: []string{"urgent"}})
+	store.SaveTask("work", &Task{ID: "22222222", Title: "Deploy web", Status: StatusTodo, Priority: 4})
+	store.SaveTask("home", &Task{ID: "33333333", Title: "Water plants", Status: StatusTodo, Priority: 1})
+
+	if out := runWithStore(NewListCmd(store, nil), "-q", "deploy priority<=2", "--ids"); out != "11111111\n" {
+		t.Errorf("Expected list to apply the query, got: %q", out)
+	}
+	if out := runWithStore(NewListCmd(store, nil), "-q", "deploy", "--status", "todo", "--ids"); strings.Count(out, "\n") != 2 {
+		t.Errorf("Expected the filter flags to combine with the query, got: %q", out)
+	}
+	if out := runWithStore(NewStatsCmd(store), "-q", "topic:work"); !strings.Contains(out, "work: 2") || strings.Contains(out, "home") {
+		t.Errorf("Expected stats to count only matching tasks, got: %s", out)
+	}
+
+	output := t.TempDir() + "/export.csv"
+	export := NewExportCmd(store)
+	export.SetArgs([]string{"-f", "csv", "-o", output, "-q", "-tag:urgent"})
+	if err := export.Execute(); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if data, _ := os.ReadFile(output); strings.Contains(string(data), "Deploy api") || !strings.Contains(string(data), "Water plants") {
+		t.Errorf("Expected export to apply the query, got: %s", data)
+	}
+
+	code, out := runExit(store, "bulk", "--complete", "-q", "prio<2")
+	if code != exitInvalidInput || !strings.Contains(out, `Invalid query: "prio<2": unknown filter "prio"`) {
+		t.Errorf("Expected a parse error, got %d: %s", code, out)
+	}
+	if out := runWithStore(NewBulkCmd(store), "--complete", "-q", "priority:1", "--tag", "urgent"); !strings.Contains(out, "complete on 1 tasks") {
+		t.Errorf("Expected bulk to complete one task, got: %s", out)
+	}
+	if archived, _ := store.LoadArchivedTasks(); len(archived["work"]) != 1 || archived["work"][0].Task.ID != "11111111" {
+		t.Errorf("Expected only the urgent task to be completed, got %v", archived["work"])
+	}
+}
+
+func TestTUISearchFilters(t *testing.T) {
+	m := model{
+		tasks: map[string][]*TaskWithPath{
+			"work": {makeTaskWithPath("Deploy api", "work", StatusTodo), makeTaskWithPath("Write docs", "work", StatusTodo)},
+			"":     {makeTaskWithPath("Water plants", "", StatusTodo)},
+		},
+		expanded: map[string]bool{},
+	}
+	m.buildItems()
+	m.searchMode = true
+	for _, r := range "deploy" {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(model)
+	}
+	out := m.viewList()
+	if !strings.Contains(out, "/deploy") || !strings.Contains(out, "Deploy api") || strings.Contains(out, "Write docs") || strings.Contains(out, "Water plants") {
+		t.Errorf("Expected the search to show only matching tasks in an open topic, got:\n%s", out)
+	}
+
+	m.setSearchQuery("stauts:todo")
+	if out := m.viewList(); !strings.Contains(out, `unknown filter "stauts"`) || !strings.Contains(out, "Water plants") {
+		t.Errorf("Expected a parse error and an unfiltered list, got:\n%s", out)
+	}
+}
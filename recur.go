@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type recurFreq int
+
+const (
+	recurDaily recurFreq = iota
+	recurWeekly
+	recurMonthly
+)
+
+// recurrence is a parsed recur rule from a task's frontmatter.
+type recurrence struct {
+	freq     recurFreq
+	interval int
+	weekdays []time.Weekday // weekly only; empty means the base date's weekday
+	monthDay int            // monthly only; 0 means the base date's day
+	// fromCompletion schedules the next instance relative to the completion
+	// date rather than the previous due date.
+	fromCompletion bool
+}
+
+var (
+	everyPattern   = regexp.MustCompile(`^every (\d+) (day|days|week|weeks|month|months)( after completion)?$`)
+	rruleWeekdays  = map[string]time.Weekday{"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday}
+	checkedPattern = regexp.MustCompile(`(?m)^(\s*[-*+] )\[[xX]\]`)
+)
+
+// parseRecurrence parses a recur rule. Supported forms are "daily",
+// "weekly", "weekly on mon,thu", "monthly", "monthly on 15",
+// "every N days|weeks|months" optionally followed by "after completion",
+// and the RRULE subset FREQ, INTERVAL, BYDAY and BYMONTHDAY.
+func parseRecurrence(rule string) (*recurrence, error) {
+	s := strings.TrimSpace(rule)
+	if upper := strings.ToUpper(s); strings.HasPrefix(upper, "RRULE:") || strings.HasPrefix(upper, "FREQ=") {
+		return parseRRule(strings.TrimPrefix(upper, "RRULE:"))
+	}
+
+	s = strings.ToLower(s)
+	r := &recurrence{interval: 1}
+	switch {
+	case s == "daily" || s == "every day":
+		r.freq = recurDaily
+	case s == "weekly" || s == "every week":
+		r.freq = recurWeekly
+	case strings.HasPrefix(s, "weekly on "):
+		r.freq = recurWeekly
+		for _, name := range strings.Split(strings.TrimPrefix(s, "weekly on "), ",") {
+			day, ok := weekdays[strings.TrimSpace(name)]
+			if !ok {
+				return nil, fmt.Errorf("invalid recur rule %q: unknown weekday %q", rule, name)
+			}
+			r.weekdays = append(r.weekdays, day)
+		}
+	case s == "monthly" || s == "every month":
+		r.freq = recurMonthly
+	case strings.HasPrefix(s, "monthly on "):
+		r.freq = recurMonthly
+		day, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(s, "monthly on "), "day "))
+		if err != nil || day < 1 || day > 31 {
+			return nil, fmt.Errorf("invalid recur rule %q: day of month must be 1-31", rule)
+		}
+		r.monthDay = day
+	default:
+		match := everyPattern.FindStringSubmatch(s)
+		if match == nil {
+			return nil, fmt.Errorf("invalid recur rule %q (try daily, weekly on mon,thu, monthly on 15, every 3 days after completion)", rule)
+		}
+		r.interval, _ = strconv.Atoi(match[1])
+		if r.interval < 1 {
+			return nil, fmt.Errorf("invalid recur rule %q: interval must be at least 1", rule)
+		}
+		switch strings.TrimSuffix(match[2], "s") {
+		case "day":
+			r.freq = recurDaily
+		case "week":
+			r.freq = recurWeekly
+		case "month":
+			r.freq = recurMonthly
+		}
+		r.fromCompletion = match[3] != ""
+	}
+	return r, nil
+}
+
+// parseRRule parses the supported subset of an iCalendar RRULE.
+func parseRRule(rule string) (*recurrence, error) {
+	r := &recurrence{interval: 1}
+	hasFreq := false
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid RRULE part %q", part)
+		}
+		switch key {
+		case "FREQ":
+			hasFreq = true
+			switch value {
+			case "DAILY":
+				r.freq = recurDaily
+			case "WEEKLY":
+				r.freq = recurWeekly
+			case "MONTHLY":
+				r.freq = recurMonthly
+			default:
+				return nil, fmt.Errorf("unsupported RRULE FREQ %q", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid RRULE INTERVAL %q", value)
+			}
+			r.interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, ok := rruleWeekdays[code]
+				if !ok {
+					return nil, fmt.Errorf("unsupported RRULE BYDAY %q", code)
+				}
+				r.weekdays = append(r.weekdays, day)
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 31 {
+				return nil, fmt.Errorf("invalid RRULE BYMONTHDAY %q", value)
+			}
+			r.monthDay = n
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part %q", key)
+		}
+	}
+	if !hasFreq {
+		return nil, fmt.Errorf("RRULE is missing FREQ")
+	}
+	return r, nil
+}
+
+// after returns the first occurrence strictly after base.
+func (r *recurrence) after(base time.Time) time.Time {
+	switch r.freq {
+	case recurWeekly:
+		if len(r.weekdays) == 0 {
+			return base.AddDate(0, 0, 7*r.interval)
+		}
+		baseWeek := weekStart(base)
+		for d := 1; d <= 7*r.interval+7; d++ {
+			candidate := base.AddDate(0, 0, d)
+			weeks := int(weekStart(candidate).Sub(baseWeek).Hours()+12) / (24 * 7)
+			if weeks%r.interval == 0 && containsWeekday(r.weekdays, candidate.Weekday()) {
+				return candidate
+			}
+		}
+		return base.AddDate(0, 0, 7*r.interval)
+	case recurMonthly:
+		day := r.monthDay
+		if day == 0 {
+			day = base.Day()
+		}
+		for k := 0; ; k += r.interval {
+			first := time.Date(base.Year(), base.Month()+time.Month(k), 1, base.Hour(), base.Minute(), 0, 0, base.Location())
+			candidate := first.AddDate(0, 0, min(day, daysIn(first))-1)
+			if candidate.After(base) {
+				return candidate
+			}
+		}
+	default:
+		return base.AddDate(0, 0, r.interval)
+	}
+}
+
+// next returns the due date of the instance following one completed at
+// completedAt. Schedules based on the previous due date skip occurrences
+// that are already in the past.
+func (r *recurrence) next(due *time.Time, completedAt time.Time) time.Time {
+	today := startOfDay(completedAt)
+	if r.fromCompletion || due == nil {
+		return r.after(today)
+	}
+	next := r.after(*due)
+	for next.Before(today.AddDate(0, 0, 1)) {
+		next = r.after(next)
+	}
+	return next
+}
+
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Monday-based weeks
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
+
+func daysIn(monthStart time.Time) int {
+	return monthStart.AddDate(0, 1, -1).Day()
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// nextInstance builds the task that follows a completed recurring task:
+// same topic and content, open checklist items, new due date.
+func nextInstance(task *Task, r *recurrence, completedAt time.Time) *Task {
+	next := r.next(task.Due, completedAt)
+	instance := &Task{
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    task.Priority,
+		Status:      initialStatus(),
+		Tags:        append([]string{}, task.Tags...),
+		Recur:       task.Recur,
+		DependsOn:   append([]string{}, task.DependsOn...),
+		Due:         &next,
+		Fields:      maps.Clone(task.Fields),
+		Body:        checkedPattern.ReplaceAllString(task.Body, "${1}[ ]"),
+		// Keys tada does not manage carry over; the managed ones are
+		// rewritten from the new instance
+		Frontmatter: task.Frontmatter,
+	}
+	if task.Scheduled != nil && task.Due != nil {
+		scheduled := next.Add(task.Scheduled.Sub(*task.Due))
+		instance.Scheduled = &scheduled
+	}
+	return instance
+}
+
+// completeTask marks t done, unless it is already closed, and archives it.
+// For a recurring task the next instance is created in the same topic and
+// returned. The next instance is written first, so an interrupted
+// completion can leave the finished instance unarchived but never loses the
+// next one. Both steps are one journal operation.
+func completeTask(store Storage, t *TaskWithPath) (*Task, error) {
+	var next *Task
+	if !t.Task.Status.isClosed() {
+		if err := checkTransition(t.Task.Status, doneStatus()); err != nil {
+			return nil, err
+		}
+	}
+	err := journaled(store, fmt.Sprintf("complete %q", t.Task.Title), func() error {
+		if t.Task.Recur != "" {
+			r, err := parseRecurrence(t.Task.Recur)
+			if err != nil {
+				return err
+			}
+			next = nextInstance(t.Task, r, time.Now())
+			if err := store.SaveTask(t.Topic, next); err != nil {
+				return fmt.Errorf("failed to create the next occurrence: %w", err)
+			}
+		}
+		if !t.Task.Status.isClosed() {
+			t.Task.setStatus(doneStatus())
+		}
+		if err := store.ArchiveTask(t); err != nil {
+			if next != nil {
+				if created, getErr := store.GetTask(next.ID); getErr == nil {
+					_ = store.PurgeTask(created)
+				}
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return next, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence_Invalid(t *testing.T) {
+	for _, rule := range []string{"sometimes", "weekly on funday", "monthly on 32", "every 0 days", "FREQ=YEARLY", "INTERVAL=2"} {
+		if _, err := parseRecurrence(rule); err == nil {
+			t.Errorf("parseRecurrence(%q): expected error", rule)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	day := func(s string) *time.Time {
+		d, err := time.ParseInLocation(dateLayout, s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &d
+	}
+
+	tests := []struct {
+		rule      string
+		due       *time.Time
+		completed string
+		want      string
+	}{
+		{"daily", day("2026-10-15"), "2026-10-15", "2026-10-16"},
+		{"weekly", day("2026-10-15"), "2026-10-15", "2026-10-22"},
+		// 12 Oct 2026 is a Monday
+		{"weekly on mon,thu", day("2026-10-12"), "2026-10-12", "2026-10-15"},
+		{"weekly on mon,thu", day("2026-10-12"), "2026-10-16", "2026-10-19"}, // completed late
+		{"monthly", day("2026-10-15"), "2026-10-15", "2026-11-15"},
+		{"monthly on 31", day("2026-01-31"), "2026-01-31", "2026-02-28"},
+		{"every 3 days after completion", day("2026-10-01"), "2026-10-15", "2026-10-18"},
+		{"every 2 weeks", day("2026-10-12"), "2026-10-12", "2026-10-26"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", day("2026-10-12"), "2026-10-12", "2026-10-26"},
+		{"RRULE:FREQ=MONTHLY;BYMONTHDAY=1", day("2026-10-15"), "2026-10-15", "2026-11-01"},
+		{"daily", nil, "2026-10-15", "2026-10-16"},
+	}
+
+	for _, tt := range tests {
+		r, err := parseRecurrence(tt.rule)
+		if err != nil {
+			t.Errorf("parseRecurrence(%q): %v", tt.rule, err)
+			continue
+		}
+		completed := day(tt.completed).Add(14 * time.Hour)
+		if got := r.next(tt.due, completed).Format(dateLayout); got != tt.want {
+			t.Errorf("%q due %v completed %s: got %s, want %s", tt.rule, formatDate(tt.due), tt.completed, got, tt.want)
+		}
+	}
+}
+
+func TestCompleteRecurringTask(t *testing.T) {
+	store := NewMemoryStorage()
+	due := startOfDay(time.Now())
+	scheduled := due.AddDate(0, 0, -1)
+	task := &Task{
+		Title:     "Dependency review",
+		Status:    StatusTodo,
+		Priority:  2,
+		Recur:     "weekly",
+		Due:       &due,
+		Scheduled: &scheduled,
+		Body:      "\n- [x] go.mod\n- [ ] package.json\n",
+	}
+	if err := store.SaveTask("chores", task); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runWithStore(NewCompleteCmd(store), "chores/Dependency review")
+	if !strings.Contains(out, "Next occurrence due: "+due.AddDate(0, 0, 7).Format(dateLayout)) {
+		t.Fatalf("Expected next occurrence in output, got: %s", out)
+	}
+
+	if len(store.archived) != 1 || store.archived[0].Task.Status != StatusDone {
+		t.Fatalf("Expected the completed instance in the archive, got %+v", store.archived)
+	}
+	if len(store.tasks) != 1 {
+		t.Fatalf("Expected one new instance, got %d", len(store.tasks))
+	}
+	next := store.tasks[0]
+	if next.Topic != "chores" || next.Task.ID == task.ID || next.Task.Status != StatusTodo {
+		t.Errorf("Unexpected next instance: %+v in %q", next.Task, next.Topic)
+	}
+	if next.Task.Recur != "weekly" || next.Task.Priority != 2 {
+		t.Errorf("Expected rule and priority to carry over, got %+v", next.Task)
+	}
+	if !next.Task.Scheduled.Equal(due.AddDate(0, 0, 6)) {
+		t.Errorf("Expected scheduled date to keep its offset, got %s", formatDate(next.Task.Scheduled))
+	}
+	if strings.Contains(next.Task.Body, "[x]") {
+		t.Errorf("Expected checklist items to be reset, got %q", next.Task.Body)
+	}
+}
+
+func TestAddRecurNone(t *testing.T) {
+	store := NewMemoryStorage()
+	runWithStore(NewAddCmd(store), "Once", "--recur", "none")
+	if len(store.tasks) != 1 || store.tasks[0].Task.Recur != "" {
+		t.Fatalf("Expected --recur none to add a task that does not repeat, got %+v", store.tasks)
+	}
+	if out := runWithStore(NewCompleteCmd(store), "Once"); !strings.Contains(out, "Task completed") {
+		t.Errorf("Expected the task to complete, got: %s", out)
+	}
+}
+
+func TestRecurringKeepsFieldsAndFrontmatter(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	path := filepath.Join(store.basePath, TasksDir, "water.md")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte("---\nid: aaaa1111\ntitle: Water plants\nstatus: todo\naliases: [ferns]\nrecur: weekly\nfields:\n  room: kitchen\n---\n"), 0644)
+
+	if out := runWithStore(NewCompleteCmd(store), "aaaa1111"); !strings.Contains(out, "Next occurrence due") {
+		t.Fatalf("Expected a next occurrence, got: %s", out)
+	}
+	tasks, _ := store.LoadAllTasks()
+	if len(tasks[""]) != 1 || tasks[""][0].Task.Fields["room"] != "kitchen" {
+		t.Fatalf("Expected the custom field to carry over, got %v", tasks[""])
+	}
+	next := tasks[""][0]
+	data, _ := os.ReadFile(next.FilePath)
+	if !strings.Contains(string(data), "aliases: [ferns]") || strings.Contains(string(data), "aaaa1111") {
+		t.Errorf("Expected unknown keys to carry over with a new ID, got:\n%s", data)
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// minIDPrefix is the shortest ID prefix accepted when resolving a task.
+const minIDPrefix = 4
+
+// errTaskNotFound and errAmbiguous are returned when a reference matches no
+// task or more than one.
+var (
+	errTaskNotFound = errors.New("task not found")
+	errAmbiguous    = errors.New("ambiguous reference")
+)
+
+// stdinIsTerminal reports whether the command's input is an interactive
+// terminal, where an ambiguous reference can be resolved by asking.
+var stdinIsTerminal = func(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	return ok && isatty.IsTerminal(f.Fd())
+}
+
+// ambiguousError is returned when a reference matches more than one task.
+type ambiguousError struct {
+	ref     string
+	matches []*TaskWithPath
+	// all is set when the command accepts --all, so it can be suggested.
+	all bool
+}
+
+func (e *ambiguousError) Error() string {
+	return fmt.Sprintf("%q matches %d tasks", e.ref, len(e.matches))
+}
+
+func (e *ambiguousError) Is(target error) bool { return target == errAmbiguous }
+
+// candidates lists the matches, numbered, one per line.
+func (e *ambiguousError) candidates() string {
+	var b strings.Builder
+	for i, t := range e.matches {
+		fmt.Fprintf(&b, "\n  %d. %s  %s  created %s  %s", i+1, t.Task.ID, t.Task.Status, t.Task.CreatedAt.Local().Format("2006-01-02 15:04"), taskRef(t))
+	}
+	return b.String()
+}
+
+// taskRef renders a task as the "[topic/]title" a command accepts.
+func taskRef(t *TaskWithPath) string {
+	if t.Topic == "" {
+		return t.Task.Title
+	}
+	return t.Topic + "/" + t.Task.Title
+}
+
+// splitTopicTitle splits a "[topic/]title" argument into topic and title.
+func splitTopicTitle(input string) (topic, title string) {
+	if i := strings.LastIndex(input, "/"); i >= 0 {
+		return input[:i], input[i+1:]
+	}
+	return "", input
+}
+
+// findTasks returns every task ref matches, oldest first. ref may be a full
+// task ID, a "[topic/]title" path, or an ID prefix of at least minIDPrefix
+// characters, tried in that order: the first kind that matches wins.
+func findTasks(tasks map[string][]*TaskWithPath, ref string) []*TaskWithPath {
+	topic, title := splitTopicTitle(ref)
+	var byTitle, byPrefix []*TaskWithPath
+	for _, taskList := range tasks {
+		for _, t := range taskList {
+			if t.Task.ID != "" && t.Task.ID == ref {
+				return []*TaskWithPath{t}
+			}
+			if t.Task.Title == title && t.Topic == topic {
+				byTitle = append(byTitle, t)
+			}
+			if len(ref) >= minIDPrefix && strings.HasPrefix(t.Task.ID, ref) {
+				byPrefix = append(byPrefix, t)
+			}
+		}
+	}
+	matches := byTitle
+	if len(matches) == 0 {
+		matches = byPrefix
+	}
+	sortMatches(matches)
+	return matches
+}
+
+// sortMatches orders the tasks a reference matched, oldest first.
+func sortMatches(matches []*TaskWithPath) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i].Task, matches[j].Task
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// refIndex is implemented by stores whose index finds the tasks a
+// reference matches without comparing it with every task.
+type refIndex interface {
+	findRef(tasks map[string][]*TaskWithPath, ref string) ([]*TaskWithPath, bool)
+}
+
+// matchRef is findTasks, served from the store's index when it has one.
+func matchRef(store Storage, tasks map[string][]*TaskWithPath, ref string) []*TaskWithPath {
+	if idx, ok := store.(refIndex); ok {
+		if matches, ok := idx.findRef(tasks, ref); ok {
+			return matches
+		}
+	}
+	return findTasks(tasks, ref)
+}
+
+// findTask resolves ref to a single task, failing with an ambiguousError
+// when it matches several.
+func findTask(tasks map[string][]*TaskWithPath, ref string) (*TaskWithPath, error) {
+	return oneMatch(findTasks(tasks, ref), ref)
+}
+
+// oneMatch returns the single task ref matched, or the error for none or
+// several.
+func oneMatch(matches []*TaskWithPath, ref string) (*TaskWithPath, error) {
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", errTaskNotFound, ref)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, &ambiguousError{ref: ref, matches: matches}
+}
+
+// resolveTasks resolves a command's task argument among the tasks loaded
+// from store. With --all, for commands that have it, every match is
+// returned. Otherwise ref must match a single task; when it matches several
+// and stdin is a terminal the user picks one.
+func resolveTasks(cmd *cobra.Command, store Storage, tasks map[string][]*TaskWithPath, ref string) ([]*TaskWithPath, error) {
+	matches := matchRef(store, tasks, ref)
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%w: %s", errTaskNotFound, ref)
+		}
+		return matches, nil
+	}
+	found, err := oneMatch(matches, ref)
+	var ambiguous *ambiguousError
+	if !errors.As(err, &ambiguous) {
+		if err != nil {
+			return nil, err
+		}
+		return []*TaskWithPath{found}, nil
+	}
+	ambiguous.all = cmd.Flags().Lookup("all") != nil
+	if !stdinIsTerminal(cmd.InOrStdin()) {
+		return nil, err
+	}
+	chosen := chooseTask(cmd, ambiguous)
+	if chosen == nil {
+		return nil, fmt.Errorf("%w: no task chosen", errAmbiguous)
+	}
+	return []*TaskWithPath{chosen}, nil
+}
+
+// resolveTargets resolves the task arguments of a command that accepts
+// several targets, in order and without repeats. Every reference must
+// resolve before the command changes anything.
+func resolveTargets(cmd *cobra.Command, store Storage, tasks map[string][]*TaskWithPath, args []string) ([]*TaskWithPath, error) {
+	refs, err := targetRefs(cmd.InOrStdin(), store, tasks, args)
+	if err != nil {
+		return nil, err
+	}
+	matches := []*TaskWithPath{}
+	seen := make(map[*TaskWithPath]bool)
+	for _, ref := range refs {
+		found, err := resolveTasks(cmd, store, tasks, ref)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range found {
+			if !seen[t] {
+				seen[t] = true
+				matches = append(matches, t)
+			}
+		}
+	}
+	return matches, nil
+}
+
+// targetRefs returns the task references in args, one per argument, with
+// "-" replaced by the lines read from in. Arguments that match no task on
+// their own but do when joined, such as an unquoted title, are read as one
+// reference.
+func targetRefs(in io.Reader, store Storage, tasks map[string][]*TaskWithPath, args []string) ([]string, error) {
+	var refs []string
+	piped := false
+	for _, arg := range args {
+		if arg != "-" {
+			refs = append(refs, arg)
+			continue
+		}
+		piped = true
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				refs = append(refs, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading task IDs from stdin: %w", err)
+		}
+	}
+	if len(refs) > 1 && !piped {
+		joined := strings.Join(args, " ")
+		for _, ref := range refs {
+			if len(matchRef(store, tasks, ref)) == 0 && len(matchRef(store, tasks, joined)) > 0 {
+				return []string{joined}, nil
+			}
+		}
+	}
+	return refs, nil
+}
+
+// resolveTask is resolveTasks for commands that act on a single task.
+func resolveTask(cmd *cobra.Command, store Storage, tasks map[string][]*TaskWithPath, ref string) (*TaskWithPath, error) {
+	matches, err := resolveTasks(cmd, store, tasks, ref)
+	if err != nil {
+		return nil, err
+	}
+	return matches[0], nil
+}
+
+// eachTask runs fn on each task a command resolved. Several tasks are
+// recorded as one journal operation, so a single undo reverts them all. A
+// failing task does not stop the others; the first failure is returned.
+func eachTask(store Storage, verb string, matches []*TaskWithPath, fn func(*TaskWithPath) error) error {
+	switch len(matches) {
+	case 0:
+		return nil
+	case 1:
+		return fn(matches[0])
+	}
+	var failed error
+	journaled(store, fmt.Sprintf("%s %d tasks", verb, len(matches)), func() error {
+		for _, t := range matches {
+			if err := fn(t); err != nil && failed == nil {
+				failed = err
+			}
+		}
+		return nil
+	})
+	return failed
+}
+
+// withTitle names the task in a success message when a command acts on
+// several tasks, so the lines can be told apart.
+func withTitle(msg string, t *TaskWithPath, several bool) string {
+	if !several {
+		return msg
+	}
+	return fmt.Sprintf("%s (%s)", strings.TrimSuffix(msg, "."), taskRef(t))
+}
+
+// chooseTask asks which of the ambiguous matches to use. It returns nil
+// unless the answer is the number of a match.
+func chooseTask(cmd *cobra.Command, ambiguous *ambiguousError) *TaskWithPath {
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s:%s\nChoose a task [1-%d]: ", ambiguous.Error(), ambiguous.candidates(), len(ambiguous.matches))
+	line, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(ambiguous.matches) {
+		return nil
+	}
+	return ambiguous.matches[n-1]
+}
+
+// lookupErrorMessage renders a findTask error for CLI output.
+func lookupErrorMessage(err error) string {
+	var ambiguous *ambiguousError
+	switch {
+	case errors.Is(err, errTaskNotFound):
+		// "task not found: ref", naming the reference among several
+		msg := err.Error()
+		return strings.ToUpper(msg[:1]) + msg[1:] + "."
+	case errors.As(err, &ambiguous):
+		hint := "Use the ID to pick one."
+		if ambiguous.all {
+			hint = "Use the ID to pick one, or --all to act on every match."
+		}
+		return fmt.Sprintf("%s:%s\n%s", ambiguous.Error(), ambiguous.candidates(), hint)
+	}
+	return fmt.Sprintf("Error: %v", err)
+}
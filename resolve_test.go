@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindTask(t *testing.T) {
+	tasks := map[string][]*TaskWithPath{
+		"": {
+			{Task: &Task{ID: "a1b2c3d4", Title: "Write docs"}},
+			{Task: &Task{ID: "a1b2ffff", Title: "Fix tests"}},
+		},
+		"work": {
+			{Task: &Task{ID: "0badcafe", Title: "Fix tests"}, Topic: "work"},
+		},
+	}
+
+	testCases := []struct {
+		ref    string
+		wantID string
+	}{
+		{"a1b2c3d4", "a1b2c3d4"},
+		{"Write docs", "a1b2c3d4"},
+		{"work/Fix tests", "0badcafe"},
+		{"0bad", "0badcafe"},
+		{"a1b2f", "a1b2ffff"},
+	}
+	for _, tc := range testCases {
+		found, err := findTask(tasks, tc.ref)
+		if err != nil {
+			t.Errorf("findTask(%q) returned error: %v", tc.ref, err)
+			continue
+		}
+		if found.Task.ID != tc.wantID {
+			t.Errorf("findTask(%q) = %s, want %s", tc.ref, found.Task.ID, tc.wantID)
+		}
+	}
+
+	if _, err := findTask(tasks, "a1b2"); err == nil || errors.Is(err, errTaskNotFound) {
+		t.Errorf("Expected ambiguous prefix error, got: %v", err)
+	}
+	if _, err := findTask(tasks, "a1b"); !errors.Is(err, errTaskNotFound) {
+		t.Errorf("Expected short prefix to be rejected, got: %v", err)
+	}
+	if _, err := findTask(tasks, "nope"); !errors.Is(err, errTaskNotFound) {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+}
+
+func TestSplitTopicTitle(t *testing.T) {
+	topic, title := splitTopicTitle("work/backend/Deploy")
+	if topic != "work/backend" || title != "Deploy" {
+		t.Errorf("Unexpected split: %q, %q", topic, title)
+	}
+	topic, title = splitTopicTitle("Deploy")
+	if topic != "" || title != "Deploy" {
+		t.Errorf("Unexpected split: %q, %q", topic, title)
+	}
+}
+
+func TestAmbiguousTitle(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{ID: "11111111", Title: "Fix tests", Status: StatusTodo, CreatedAt: time.Now().Add(-time.Hour)})
+	store.SaveTask("work", &Task{ID: "22222222", Title: "Fix tests", Status: StatusInProgress})
+
+	code, out := runExit(store, "complete", "work/Fix tests")
+	if code != exitAmbiguous || !strings.Contains(out, "1. 11111111  todo") || !strings.Contains(out, "2. 22222222  in-progress") || !strings.Contains(out, "--all") {
+		t.Errorf("Expected the candidates to be listed, got %d: %s", code, out)
+	}
+	if err := store.CompleteTask("work", "Fix tests"); !errors.Is(err, errAmbiguous) {
+		t.Errorf("Expected CompleteTask to refuse an ambiguous title, got %v", err)
+	}
+	if archived, _ := store.LoadArchivedTasks(); len(archived["work"]) != 0 {
+		t.Fatalf("Expected nothing to be completed, got %d archived", len(archived["work"]))
+	}
+
+	// On a terminal the user picks one
+	orig := stdinIsTerminal
+	stdinIsTerminal = func(io.Reader) bool { return true }
+	t.Cleanup(func() { stdinIsTerminal = orig })
+	pick := func(answer string, args ...string) string {
+		cmd := NewEditCmd(store)
+		cmd.SetIn(strings.NewReader(answer))
+		return runWithStore(cmd, args...)
+	}
+	if out := pick("2\n", "work/Fix tests", "-p", "1"); !strings.Contains(out, "Choose a task [1-2]") || !strings.Contains(out, "Task updated") {
+		t.Errorf("Expected to be asked, got: %s", out)
+	}
+	if task, _ := store.GetTask("22222222"); task.Task.Priority != 1 {
+		t.Errorf("Expected the chosen task to be edited, got priority %d", task.Task.Priority)
+	}
+	if out := pick("\n", "work/Fix tests", "-p", "2"); !strings.Contains(out, "no task chosen") {
+		t.Errorf("Expected an empty answer to cancel, got: %s", out)
+	}
+
+	// --all acts on every match as one undoable change
+	if out := runWithStore(NewEditCmd(store), "work/Fix tests", "--all", "-p", "5"); strings.Count(out, "Task updated (work/Fix tests)") != 2 {
+		t.Errorf("Expected both tasks to be updated, got: %s", out)
+	}
+	runWithStore(NewUndoCmd(store))
+	first, _ := store.GetTask("11111111")
+	second, _ := store.GetTask("22222222")
+	if first.Task.Priority == 5 || second.Task.Priority != 1 {
+		t.Errorf("Expected one undo to revert both, got priorities %d and %d", first.Task.Priority, second.Task.Priority)
+	}
+}
+
+func TestMultipleTargets(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{ID: "11111111", Title: "Deploy api", Status: StatusTodo})
+	store.SaveTask("work", &Task{ID: "22222222", Title: "Deploy web", Status: StatusTodo})
+	store.SaveTask("home", &Task{ID: "33333333", Title: "Water plants", Status: StatusTodo})
+
+	// Several IDs, with an unquoted title still read as one reference
+	if out := runWithStore(NewEditCmd(store), "11111111", "33333333", "-p", "1"); strings.Count(out, "Task updated") != 2 {
+		t.Errorf("Expected two tasks to be updated, got: %s", out)
+	}
+	if out := runWithStore(NewShowCmd(store), "home/Water", "plants"); !strings.Contains(out, "ID: 33333333") {
+		t.Errorf("Expected an unquoted title to still work, got: %s", out)
+	}
+
+	// Nothing changes when one of the targets is missing
+	code, out := runExit(store, "complete", "11111111", "missing")
+	if code != exitNotFound || !strings.Contains(out, "Task not found: missing") {
+		t.Errorf("Expected the missing target to be named, got %d: %s", code, out)
+	}
+	if archived, _ := store.LoadArchivedTasks(); len(archived["work"]) != 0 {
+		t.Fatalf("Expected nothing to be completed, got %d archived", len(archived["work"]))
+	}
+
+	// tada list -q deploy --ids | tada complete -
+	ids := runWithStore(NewListCmd(store, nil), "-q", "deploy", "--ids")
+	if lines := strings.Fields(ids); len(lines) != 2 || !strings.Contains(ids, "11111111\n") || !strings.Contains(ids, "22222222\n") {
+		t.Fatalf("Expected bare IDs, got: %q", ids)
+	}
+	complete := NewCompleteCmd(store)
+	complete.SetIn(strings.NewReader(ids))
+	if out := runWithStore(complete, "-"); strings.Count(out, "Task completed") != 2 {
+		t.Errorf("Expected both piped tasks to be completed, got: %s", out)
+	}
+	if archived, _ := store.LoadArchivedTasks(); len(archived["work"]) != 2 {
+		t.Errorf("Expected both tasks to be archived, got %d", len(archived["work"]))
+	}
+
+	// An empty pipe does nothing
+	move := NewMoveCmd(store)
+	move.SetIn(strings.NewReader(""))
+	if out := runWithStore(move, "-", "garden"); strings.Contains(out, "Error") || strings.Contains(out, "moved") {
+		t.Errorf("Expected an empty pipe to be a no-op, got: %s", out)
+	}
+}
+
+func TestEachTaskReturnsFirstFailure(t *testing.T) {
+	store := NewMemoryStorage()
+	matches := []*TaskWithPath{{Task: &Task{Title: "a"}}, {Task: &Task{Title: "b"}}, {Task: &Task{Title: "c"}}}
+	var ran []string
+	err := eachTask(store, "edit", matches, func(t *TaskWithPath) error {
+		ran = append(ran, t.Task.Title)
+		if t.Task.Title == "a" {
+			return errTaskNotFound
+		}
+		return errConflict
+	})
+	if !errors.Is(err, errTaskNotFound) || len(ran) != 3 {
+		t.Errorf("Expected every task to run and the first failure to be returned, got %v after %v", err, ran)
+	}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	fstore "io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -16,10 +19,19 @@ const (
 	TadaDir    = ".tada"
 	ArchiveDir = "archive"
 	TasksDir   = "tasks"
+	TrashDir   = "trash"
 )
 
+// idLength is the number of hex characters in a generated task ID.
+const idLength = 8
+
 type FileStore struct {
 	basePath string
+
+	// journalMu guards the operation being collected for the journal
+	journalMu sync.Mutex
+	pending   *journalEntry
+	depth     int
 }
 
 func NewFileStore(basePath ...string) *FileStore {
@@ -48,6 +60,31 @@ func (fs *FileStore) ensureDirectories() error {
 }
 
 func (fs *FileStore) generateFileName(title string) string {
+	// Add timestamp
+	timestamp := time.Now().Format(fileTimestampLayout)
+	return fmt.Sprintf("%s-%s.md", timestamp, slugify(title))
+}
+
+// newFilePath returns a path in dir for a new task file named after title.
+// Files created within the same second get a numeric suffix instead of
+// overwriting each other.
+func (fs *FileStore) newFilePath(dir, title string) string {
+	name := fs.generateFileName(title)
+	path := filepath.Join(dir, name)
+	for n := 2; ; n++ {
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d.md", strings.TrimSuffix(name, ".md"), n))
+	}
+}
+
+// fileTimestampLayout is the time prefix of generated task file names.
+const fileTimestampLayout = "20060102-150405"
+
+// slugify turns a title into the lowercase, dash-separated form used in
+// task file names.
+func slugify(title string) string {
 	// Create slug from title
 	slug := strings.ToLower(title)
 	slug = strings.ReplaceAll(slug, " ", "-")
@@ -68,11 +105,39 @@ func (fs *FileStore) generateFileName(title string) string {
 	slug = result.String()
 
 	// Remove leading/trailing dashes
-	slug = strings.Trim(slug, "-")
+	return strings.Trim(slug, "-")
+}
 
-	// Add timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	return fmt.Sprintf("%s-%s.md", timestamp, slug)
+// newTaskID returns a random hex ID that is not already in taken.
+func newTaskID(taken map[string]bool) string {
+	buf := make([]byte, idLength/2)
+	for {
+		if _, err := rand.Read(buf); err != nil {
+			// crypto/rand never fails on supported platforms; fall back to the clock
+			return fmt.Sprintf("%0*x", idLength, time.Now().UnixNano())[:idLength]
+		}
+		id := hex.EncodeToString(buf)
+		if !taken[id] {
+			return id
+		}
+	}
+}
+
+// taskIDs collects the IDs of every active, archived and deleted task.
+func (fs *FileStore) taskIDs() (map[string]bool, error) {
+	ids := make(map[string]bool)
+	for _, dir := range []string{TasksDir, ArchiveDir, TrashDir} {
+		tasks, err := fs.loadTree(filepath.Join(fs.basePath, dir))
+		if err != nil {
+			return nil, err
+		}
+		for _, taskList := range tasks {
+			for _, t := range taskList {
+				ids[t.Task.ID] = true
+			}
+		}
+	}
+	return ids, nil
 }
 
 func (fs *FileStore) SaveTask(topic string, task *Task) error {
@@ -84,6 +149,22 @@ func (fs *FileStore) SaveTask(topic string, task *Task) error {
 	if task.CreatedAt.IsZero() {
 		task.CreatedAt = time.Now()
 	}
+	// Start the status history with the initial status
+	if len(task.History) == 0 {
+		task.History = []StatusChange{{To: task.Status, At: task.CreatedAt}}
+	}
+
+	return fs.withLock(func() error {
+		return fs.saveTask(topic, task)
+	})
+}
+
+// saveTask writes a new task file; the caller holds the lock.
+func (fs *FileStore) saveTask(topic string, task *Task) error {
+	// Assign a stable ID on first save
+	if err := fs.assignID(task); err != nil {
+		return err
+	}
 
 	// Create topic directory if needed
 	topicPath := filepath.Join(fs.basePath, TasksDir, topic)
@@ -96,16 +177,17 @@ func (fs *FileStore) SaveTask(topic string, task *Task) error {
 	}
 
 	// Generate filename
-	filename := fs.generateFileName(task.Title)
-	filePath := filepath.Join(topicPath, filename)
+	filePath := fs.newFilePath(topicPath, task.Title)
 
 	// Create markdown content
+	touch(task)
 	content := fs.taskToMarkdown(task)
 
 	// Write file
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+	if err := writeFileAtomic(filePath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write task file: %w", err)
 	}
+	fs.record(fmt.Sprintf("add %q", task.Title), fs.change(filePath, nil, []byte(content)))
 
 	return nil
 }
@@ -113,14 +195,33 @@ func (fs *FileStore) SaveTask(topic string, task *Task) error {
 func (fs *FileStore) taskToMarkdown(task *Task) string {
 	var content strings.Builder
 
-	// YAML frontmatter
+	// YAML frontmatter, which becomes the base for the next write
 	content.WriteString("---\n")
-	yamlData, _ := yaml.Marshal(task)
+	yamlData := marshalFrontmatter(task)
+	task.Frontmatter = string(yamlData)
 	content.Write(yamlData)
-	content.WriteString("---\n\n")
+	content.WriteString("---\n")
 
 	// Markdown content
-	content.WriteString(fmt.Sprintf("# %s\n\n", task.Title))
+	if task.Body != "" {
+		content.WriteString(task.Body)
+	} else {
+		content.WriteString(defaultTaskBody(task))
+	}
+
+	return content.String()
+}
+
+// touch records that task is being written now.
+func touch(task *Task) {
+	now := time.Now()
+	task.UpdatedAt = &now
+}
+
+// defaultTaskBody is the Markdown body written for tasks that have none.
+func defaultTaskBody(task *Task) string {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("\n# %s\n\n", task.Title))
 
 	if task.Description != "" {
 		content.WriteString(fmt.Sprintf("%s\n\n", task.Description))
@@ -133,21 +234,61 @@ func (fs *FileStore) LoadAllTasks() (map[string][]*TaskWithPath, error) {
 	if err := fs.ensureDirectories(); err != nil {
 		return nil, err
 	}
+	return fs.loadTree(filepath.Join(fs.basePath, TasksDir))
+}
+
+// LoadArchivedTasks loads every task under the archive directory.
+func (fs *FileStore) LoadArchivedTasks() (map[string][]*TaskWithPath, error) {
+	if err := fs.ensureDirectories(); err != nil {
+		return nil, err
+	}
+	return fs.loadTree(filepath.Join(fs.basePath, ArchiveDir))
+}
+
+// LoadTrashedTasks loads every task under the trash directory.
+func (fs *FileStore) LoadTrashedTasks() (map[string][]*TaskWithPath, error) {
+	if err := fs.ensureDirectories(); err != nil {
+		return nil, err
+	}
+	return fs.loadTree(filepath.Join(fs.basePath, TrashDir))
+}
 
+// loadTree loads every task file below root, grouped by topic.
+func (fs *FileStore) loadTree(root string) (map[string][]*TaskWithPath, error) {
 	tasks := make(map[string][]*TaskWithPath)
-	tasksPath := filepath.Join(fs.basePath, TasksDir)
 
-	err := filepath.WalkDir(tasksPath, func(path string, d fstore.DirEntry, err error) error {
+	// Parsed files are cached in the index; only new or changed files are read
+	idx := fs.loadIndex()
+	scanStart := time.Now().UnixNano()
+	rootKey, _ := fs.indexKey(root)
+	seen := make(map[string]bool)
+	dirty := false
+
+	err := filepath.WalkDir(root, func(path string, d fstore.DirEntry, err error) error {
 		if err != nil {
+			if path == root && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
 			return err
 		}
 
-		if d.IsDir() || !strings.HasSuffix(path, ".md") {
+		if d.IsDir() {
+			// Directories are recorded so lookups can tell whether files
+			// were added or removed since
+			if key, indexed := fs.indexKey(path); indexed {
+				if info, err := d.Info(); err == nil {
+					seen[key] = true
+					dirty = idx.storeDir(key, info) || dirty
+				}
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") {
 			return nil
 		}
 
 		// Calculate relative topic path
-		relPath, err := filepath.Rel(tasksPath, path)
+		relPath, err := filepath.Rel(root, path)
 		if err != nil {
 			return err
 		}
@@ -157,8 +298,25 @@ func (fs *FileStore) LoadAllTasks() (map[string][]*TaskWithPath, error) {
 			topic = ""
 		}
 
+		key, indexed := fs.indexKey(path)
+		info, err := d.Info()
+		if err != nil {
+			return nil // removed while walking
+		}
+		if indexed {
+			seen[key] = true
+			if task, hash, ok := idx.cached(rootKey, key, info); ok {
+				tasks[topic] = append(tasks[topic], &TaskWithPath{Task: task, FilePath: path, Topic: topic, Hash: hash})
+				return nil
+			}
+		}
+
 		// Load task
-		task, err := fs.loadTaskFromFile(path)
+		content, err := os.ReadFile(path)
+		var task *Task
+		if err == nil {
+			task, err = parseTask(content)
+		}
 		if err != nil {
 			styledWarn := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Warning: failed to load task from %s: %v", path, err))
 			fmt.Fprintln(os.Stderr, styledWarn)
@@ -169,21 +327,127 @@ func (fs *FileStore) LoadAllTasks() (map[string][]*TaskWithPath, error) {
 			Task:     task,
 			FilePath: path,
 			Topic:    topic,
+			Hash:     contentHash(content),
+		}
+		if indexed {
+			cached := *task
+			idx.store(key, info, taskWithPath.Hash, &cached)
+			dirty = true
 		}
 
 		tasks[topic] = append(tasks[topic], taskWithPath)
 		return nil
 	})
 
+	if err == nil {
+		if rootKey != "" {
+			dirty = idx.prune(rootKey+"/", seen) || dirty
+		}
+		if dirty {
+			idx.Scanned[rootKey] = scanStart
+			fs.saveIndex(idx)
+		}
+	}
 	return tasks, err
 }
 
-func (fs *FileStore) loadTaskFromFile(filePath string) (*Task, error) {
-	content, err := os.ReadFile(filePath)
+// assignID gives task a new ID if it has none. Task files written before
+// IDs existed get theirs the next time they are written, or from tada
+// doctor --fix. The caller holds the lock.
+func (fs *FileStore) assignID(task *Task) error {
+	if task.ID != "" {
+		return nil
+	}
+	taken, err := fs.taskIDs()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	task.ID = newTaskID(taken)
+	return nil
+}
+
+// backfillIDs migrates task files written before IDs existed: each loaded
+// task without an ID is given one, which is persisted back to its file.
+func (fs *FileStore) backfillIDs(tasks map[string][]*TaskWithPath) error {
+	var missing []*TaskWithPath
+	for _, taskList := range tasks {
+		for _, t := range taskList {
+			if t.Task.ID == "" {
+				missing = append(missing, t)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return fs.withLock(func() error {
+		taken, err := fs.taskIDs()
+		if err != nil {
+			return err
+		}
+		for _, t := range missing {
+			if err := fs.backfillTaskID(t, taken); err != nil {
+				styledWarn := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Warning: failed to record ID in %s: %v", t.FilePath, err))
+				fmt.Fprintln(os.Stderr, styledWarn)
+			}
+		}
+		return nil
+	})
+}
+
+// backfillTaskID records a new ID in t's frontmatter, leaving the rest of
+// the file untouched. If another process got there first, its ID is kept.
+func (fs *FileStore) backfillTaskID(t *TaskWithPath, taken map[string]bool) error {
+	content, err := os.ReadFile(t.FilePath)
+	if err != nil {
+		return err
+	}
+	if current, err := parseTask(content); err == nil && current.ID != "" {
+		t.Task.ID = current.ID
+		t.Hash = contentHash(content)
+		return nil
+	}
+	id := newTaskID(taken)
+	updated, err := withTaskID(content, id)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(t.FilePath, updated, 0644); err != nil {
+		return err
+	}
+	taken[id] = true
+	t.Task.ID = id
+	t.Hash = contentHash(updated)
+	return nil
+}
+
+// withTaskID returns content with its frontmatter id key set to id. Any
+// existing id line is dropped and the new one is placed first.
+func withTaskID(content []byte, id string) ([]byte, error) {
+	contentStr := string(content)
+	if !strings.HasPrefix(contentStr, "---\n") {
+		return nil, fmt.Errorf("invalid task file format: missing YAML frontmatter")
+	}
+	lines := strings.SplitAfter(contentStr[4:], "\n")
+	var out strings.Builder
+	out.WriteString("---\nid: " + id + "\n")
+	inFrontmatter := true
+	for _, line := range lines {
+		if inFrontmatter {
+			if line == "---\n" || line == "---" {
+				inFrontmatter = false
+			} else if strings.HasPrefix(line, "id:") {
+				continue
+			}
+		}
+		out.WriteString(line)
 	}
+	return []byte(out.String()), nil
+}
 
+// parseTask parses a task file's frontmatter and body.
+func parseTask(content []byte) (*Task, error) {
 	// Parse YAML frontmatter
 	contentStr := string(content)
 	if !strings.HasPrefix(contentStr, "---\n") {
@@ -199,61 +463,312 @@ func (fs *FileStore) loadTaskFromFile(filePath string) (*Task, error) {
 	if err := yaml.Unmarshal([]byte(parts[0]), &task); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML frontmatter: %w", err)
 	}
+	task.Frontmatter = parts[0]
+	if len(parts) == 2 {
+		task.Body = parts[1]
+	}
 
 	return &task, nil
 }
 
 func (fs *FileStore) CompleteTask(topic, title string) error {
-	// Find the task file
-	tasks, err := fs.LoadAllTasks()
+	ref := title
+	if topic != "" {
+		ref = topic + "/" + title
+	}
+	matches, err := fs.lookup(func(idx *taskIndex) []string { return idx.Titles[ref] }, func(t string, task *Task) bool {
+		return t == topic && task.Title == title
+	})
 	if err != nil {
 		return err
 	}
 
-	var targetTask *TaskWithPath
-	for t, taskList := range tasks {
-		if t == topic {
-			for _, task := range taskList {
-				if task.Task.Title == title {
-					targetTask = task
-					break
-				}
-			}
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("%w: %s", errTaskNotFound, title)
+	case 1:
+		_, err = completeTask(fs, matches[0])
+		return err
+	}
+	return &ambiguousError{ref: title, matches: matches}
+}
+
+// ArchiveTask marks an open task done and moves it into the archive,
+// keeping its topic. Tasks that are already closed keep their status.
+func (fs *FileStore) ArchiveTask(targetTask *TaskWithPath) error {
+	archivePath := fs.topicDir(ArchiveDir, targetTask.Topic)
+	if err := os.MkdirAll(archivePath, 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	// Record the new status in place, then rename into the archive under a
+	// new unique filename. The status changes on a copy, so targetTask is
+	// left as it was if archiving fails.
+	err := fs.withLock(func() error {
+		archived := targetTask.clone()
+		if !archived.Task.Status.isClosed() {
+			archived.Task.setStatus(doneStatus())
+		}
+		if archived.Task.CompletedAt == nil {
+			now := time.Now()
+			archived.Task.CompletedAt = &now
+		}
+		newPath := fs.newFilePath(archivePath, archived.Task.Title)
+		if err := fs.rewriteAndMove(archived, newPath, fmt.Sprintf("archive %q", archived.Task.Title)); err != nil {
+			return err
 		}
+		targetTask.adopt(archived)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive task: %w", err)
+	}
+	return nil
+}
+
+func (fs *FileStore) GetTask(id string) (*TaskWithPath, error) {
+	found, err := fs.lookup(func(idx *taskIndex) []string { return idx.IDs[id] }, func(_ string, t *Task) bool { return t.ID == id })
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("%w: %s", errTaskNotFound, id)
+	}
+	return found[0], nil
+}
+
+// UpdateTask rewrites t's file. It fails with errConflict if the file was
+// changed by another process since t was loaded.
+func (fs *FileStore) UpdateTask(t *TaskWithPath) error {
+	return fs.withLock(func() error {
+		return fs.updateTask(t)
+	})
+}
+
+// updateTask rewrites t's file; the caller holds the lock.
+func (fs *FileStore) updateTask(t *TaskWithPath) error {
+	if err := checkUnchanged(t); err != nil {
+		return err
+	}
+	if err := fs.assignID(t.Task); err != nil {
+		return err
+	}
+	before := snapshot(t.FilePath)
+	touch(t.Task)
+	content := []byte(fs.taskToMarkdown(t.Task))
+	if err := writeFileAtomic(t.FilePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write task file: %w", err)
 	}
+	t.Hash = contentHash(content)
+	fs.record(fmt.Sprintf("edit %q", t.Task.Title), fs.change(t.FilePath, before, content))
+	return nil
+}
+
+// UpdateTasks calls fn with the active tasks as they are on disk and
+// writes the tasks it returns, all under one lock.
+func (fs *FileStore) UpdateTasks(fn func(tasks map[string][]*TaskWithPath) ([]*TaskWithPath, error)) error {
+	return fs.withLock(func() error {
+		tasks, err := fs.loadTree(filepath.Join(fs.basePath, TasksDir))
+		if err != nil {
+			return err
+		}
+		changed, err := fn(tasks)
+		if err != nil {
+			return err
+		}
+		for _, t := range changed {
+			if err := fs.updateTask(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
 
-	if targetTask == nil {
-		return fmt.Errorf("task not found: %s", title)
+// DeleteTask moves t into the trash, keeping its topic and recording when
+// and where it was deleted. It fails with errConflict if the file was
+// changed by another process since t was loaded.
+func (fs *FileStore) DeleteTask(t *TaskWithPath) error {
+	trashDir := fs.topicDir(TrashDir, t.Topic)
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
 	}
 
-	// Update task status
-	targetTask.Task.Status = StatusDone
+	// A deleted task stops being tracked
 	now := time.Now()
-	targetTask.Task.CompletedAt = &now
+	running := t.Task.runningEntry()
+	t.Task.stopTimer(now)
+	t.Task.DeletedAt = &now
+	t.Task.DeletedFrom = filepath.ToSlash(t.FilePath)
+	if key, ok := fs.indexKey(t.FilePath); ok {
+		t.Task.DeletedFrom = key
+	}
+	err := fs.withLock(func() error {
+		return fs.rewriteAndMove(t, fs.targetPath(trashDir, t.FilePath, t.Task.Title), fmt.Sprintf("delete %q", t.Task.Title))
+	})
+	if err != nil {
+		if running != nil {
+			running.End = nil
+		}
+		t.Task.DeletedAt = nil
+		t.Task.DeletedFrom = ""
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil
+}
 
-	// Move to archive
-	archivePath := filepath.Join(fs.basePath, ArchiveDir)
-	if topic != "" {
-		archivePath = filepath.Join(fs.basePath, ArchiveDir, topic)
+// RestoreTrashedTask moves a deleted task back to the file it was deleted
+// from, or into its topic under a new name if that file was taken since.
+func (fs *FileStore) RestoreTrashedTask(t *TaskWithPath) error {
+	newPath := ""
+	if from := t.Task.DeletedFrom; from != "" {
+		newPath = filepath.FromSlash(from)
+		if !filepath.IsAbs(newPath) {
+			newPath = filepath.Join(fs.basePath, newPath)
+		}
+		if _, err := os.Stat(newPath); err == nil {
+			newPath = ""
+		}
 	}
-	if err := os.MkdirAll(archivePath, 0755); err != nil {
-		return fmt.Errorf("failed to create archive directory: %w", err)
+	if newPath == "" {
+		newPath = fs.targetPath(fs.topicDir(TasksDir, t.Topic), t.FilePath, t.Task.Title)
+	}
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create topic directory: %w", err)
+	}
+
+	deletedAt, deletedFrom := t.Task.DeletedAt, t.Task.DeletedFrom
+	t.Task.DeletedAt = nil
+	t.Task.DeletedFrom = ""
+	err := fs.withLock(func() error {
+		return fs.rewriteAndMove(t, newPath, fmt.Sprintf("restore %q from trash", t.Task.Title))
+	})
+	if err != nil {
+		t.Task.DeletedAt, t.Task.DeletedFrom = deletedAt, deletedFrom
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+	return nil
+}
+
+// PurgeTask permanently removes a task file. It fails with errConflict if
+// the file was changed by another process since t was loaded.
+func (fs *FileStore) PurgeTask(t *TaskWithPath) error {
+	return fs.withLock(func() error {
+		if err := checkUnchanged(t); err != nil {
+			return err
+		}
+		before := snapshot(t.FilePath)
+		if err := os.Remove(t.FilePath); err != nil {
+			return fmt.Errorf("failed to delete task file: %w", err)
+		}
+		t.Hash = ""
+		fs.record(fmt.Sprintf("purge %q", t.Task.Title), fs.change(t.FilePath, before, nil))
+		return nil
+	})
+}
+
+// topicLabel names a topic for messages, with "." for the root.
+func topicLabel(topic string) string {
+	if topic == "" {
+		return "."
 	}
+	return topic
+}
 
-	// Always generate a new unique filename for the archive
-	filename := fs.generateFileName(targetTask.Task.Title)
-	newPath := filepath.Join(archivePath, filename)
+// topicDir returns the directory holding tasks for topic below dir.
+func (fs *FileStore) topicDir(dir, topic string) string {
+	if topic == "" {
+		return filepath.Join(fs.basePath, dir)
+	}
+	return filepath.Join(fs.basePath, dir, topic)
+}
+
+// targetPath picks a file path in newDir for a task moved or copied from
+// oldPath, keeping the original file name unless it is already taken.
+func (fs *FileStore) targetPath(newDir, oldPath, title string) string {
+	newPath := filepath.Join(newDir, filepath.Base(oldPath))
+	if _, err := os.Stat(newPath); err == nil {
+		newPath = fs.newFilePath(newDir, title)
+	}
+	return newPath
+}
 
-	// Save updated task to archive
-	content := fs.taskToMarkdown(targetTask.Task)
-	if err := os.WriteFile(newPath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write archived task: %w", err)
+func (fs *FileStore) MoveTask(t *TaskWithPath, topic string) error {
+	newDir := fs.topicDir(TasksDir, topic)
+	if err := os.MkdirAll(newDir, 0755); err != nil {
+		return fmt.Errorf("failed to create topic directory: %w", err)
 	}
+	return fs.withLock(func() error {
+		if err := checkUnchanged(t); err != nil {
+			return err
+		}
+		newPath := fs.targetPath(newDir, t.FilePath, t.Task.Title)
+		content := snapshot(t.FilePath)
+		if err := renameDurable(t.FilePath, newPath); err != nil {
+			return fmt.Errorf("failed to move task file: %w", err)
+		}
+		fs.record(fmt.Sprintf("move %q to %s", t.Task.Title, topicLabel(topic)),
+			fs.change(t.FilePath, content, nil), fs.change(newPath, nil, content))
+		t.FilePath = newPath
+		t.Topic = topic
+		return nil
+	})
+}
 
-	// Remove original file
-	if err := os.Remove(targetTask.FilePath); err != nil {
-		return fmt.Errorf("failed to remove original task file: %w", err)
+func (fs *FileStore) CopyTask(t *TaskWithPath, topic string) (*TaskWithPath, error) {
+	newDir := fs.topicDir(TasksDir, topic)
+	if err := os.MkdirAll(newDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create topic directory: %w", err)
 	}
+	var copied *TaskWithPath
+	err := fs.withLock(func() error {
+		content, err := os.ReadFile(t.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read task file: %w", err)
+		}
+		task, err := parseTask(content)
+		if err != nil {
+			return err
+		}
+
+		// The copy is a separate task, so it needs its own ID and history
+		taken, err := fs.taskIDs()
+		if err != nil {
+			return err
+		}
+		task.resetForCopy(newTaskID(taken), time.Now())
+		data := []byte(fs.taskToMarkdown(task))
+
+		newPath := fs.targetPath(newDir, t.FilePath, t.Task.Title)
+		if err := writeFileAtomic(newPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to write task file: %w", err)
+		}
+		fs.record(fmt.Sprintf("copy %q to %s", t.Task.Title, topicLabel(topic)), fs.change(newPath, nil, data))
+
+		copied = &TaskWithPath{Task: task, FilePath: newPath, Topic: topic, Hash: contentHash(data)}
+		return nil
+	})
+	return copied, err
+}
 
+func (fs *FileStore) RestoreTask(t *TaskWithPath) error {
+	newDir := fs.topicDir(TasksDir, t.Topic)
+	if err := os.MkdirAll(newDir, 0755); err != nil {
+		return fmt.Errorf("failed to create topic directory: %w", err)
+	}
+	// As in ArchiveTask, t changes only once the task is restored
+	err := fs.withLock(func() error {
+		restored := t.clone()
+		restored.Task.setStatus(initialStatus())
+		restored.Task.CompletedAt = nil
+		if err := fs.rewriteAndMove(restored, fs.targetPath(newDir, t.FilePath, t.Task.Title), fmt.Sprintf("restore %q", t.Task.Title)); err != nil {
+			return err
+		}
+		t.adopt(restored)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
 	return nil
 }
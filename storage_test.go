@@ -257,3 +257,237 @@ func TestTaskToMarkdown(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+// TestTaskIDs tests ID assignment on save and on writing or backfilling
+// legacy files
+func TestTaskIDs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "tada-id-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	fs := NewFileStore(tempDir)
+
+	first := &Task{Title: "First", Status: StatusTodo}
+	second := &Task{Title: "Second", Status: StatusTodo}
+	if err := fs.SaveTask("", first); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	if err := fs.SaveTask("", second); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	if len(first.ID) != idLength || first.ID == second.ID {
+		t.Errorf("Expected distinct %d-char IDs, got %q and %q", idLength, first.ID, second.ID)
+	}
+
+	// A file written before IDs existed is left alone by reads
+	legacy := "---\ntitle: Legacy\nstatus: todo\n---\n\n# Legacy\n\nhand-written notes\n"
+	legacyPath := filepath.Join(tempDir, TasksDir, "legacy.md")
+	if err := os.WriteFile(legacyPath, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write legacy task: %v", err)
+	}
+	tasks, err := fs.LoadAllTasks()
+	if err != nil {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+	if data, _ := os.ReadFile(legacyPath); string(data) != legacy {
+		t.Errorf("Expected loading not to rewrite the legacy task, got:\n%s", data)
+	}
+
+	// tada doctor --fix gives it an ID without touching the body
+	if err := fs.backfillIDs(tasks); err != nil {
+		t.Fatalf("Failed to backfill IDs: %v", err)
+	}
+	var legacyID string
+	for _, task := range tasks[""] {
+		if task.Task.Title == "Legacy" {
+			legacyID = task.Task.ID
+		}
+	}
+	if legacyID == "" {
+		t.Fatalf("Expected legacy task to be assigned an ID")
+	}
+	data, _ := os.ReadFile(legacyPath)
+	if !strings.HasPrefix(string(data), "---\nid: "+legacyID+"\n") || !strings.Contains(string(data), "hand-written notes") {
+		t.Errorf("Expected ID to be backfilled without touching the body, got:\n%s", data)
+	}
+
+	// Writing a legacy task gives it an ID too
+	otherPath := filepath.Join(tempDir, TasksDir, "other.md")
+	os.WriteFile(otherPath, []byte("---\ntitle: Other\nstatus: todo\n---\n"), 0644)
+	tasks, _ = fs.LoadAllTasks()
+	for _, task := range tasks[""] {
+		if task.Task.Title == "Other" {
+			task.Task.Priority = 2
+			if err := fs.UpdateTask(task); err != nil {
+				t.Fatalf("Failed to update task: %v", err)
+			}
+			if reloaded, err := fs.GetTask(task.Task.ID); err != nil || task.Task.ID == "" || reloaded.Task.Priority != 2 {
+				t.Errorf("Expected the update to assign an ID, got %q: %v", task.Task.ID, err)
+			}
+		}
+	}
+
+	// The ID is stable across loads and survives archiving
+	tasks, _ = fs.LoadAllTasks()
+	for _, task := range tasks[""] {
+		if task.Task.Title == "Legacy" && task.Task.ID != legacyID {
+			t.Errorf("Expected stable ID %s, got %s", legacyID, task.Task.ID)
+		}
+	}
+	if err := fs.CompleteTask("", "First"); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	archived, _ := fs.loadTree(filepath.Join(tempDir, ArchiveDir))
+	if len(archived[""]) != 1 || archived[""][0].Task.ID != first.ID {
+		t.Errorf("Expected archived task to keep ID %s", first.ID)
+	}
+}
+
+// TestFileStoreMutations tests the Storage mutation methods of FileStore
+func TestFileStoreMutations(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "tada-mutation-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	var fs Storage = NewFileStore(tempDir)
+
+	task := &Task{Title: "Mutate Me", Status: StatusTodo}
+	if err := fs.SaveTask("", task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	found, err := fs.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task by ID: %v", err)
+	}
+
+	found.Task.Description = "updated"
+	if err := fs.UpdateTask(found); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if reloaded, _ := fs.GetTask(task.ID); reloaded.Task.Description != "updated" {
+		t.Errorf("Expected updated description, got %q", reloaded.Task.Description)
+	}
+
+	if err := fs.MoveTask(found, "work"); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+	if found.Topic != "work" || !strings.Contains(found.FilePath, filepath.Join(TasksDir, "work")) {
+		t.Errorf("Expected task to be moved to work, got topic %q path %q", found.Topic, found.FilePath)
+	}
+
+	copied, err := fs.CopyTask(found, "home")
+	if err != nil {
+		t.Fatalf("Failed to copy task: %v", err)
+	}
+	if copied.Task.ID == found.Task.ID || copied.Topic != "home" {
+		t.Errorf("Expected copy with a new ID in home, got %+v", copied)
+	}
+	if reloaded, err := fs.GetTask(copied.Task.ID); err != nil || reloaded.Task.Description != "updated" {
+		t.Errorf("Expected copy to be loadable with original content, got %v", err)
+	}
+
+	if err := fs.ArchiveTask(found); err != nil {
+		t.Fatalf("Failed to archive task: %v", err)
+	}
+	if _, err := fs.GetTask(task.ID); err == nil {
+		t.Errorf("Expected archived task to leave the active tasks")
+	}
+	if err := fs.RestoreTask(found); err != nil {
+		t.Fatalf("Failed to restore task: %v", err)
+	}
+	restored, err := fs.GetTask(task.ID)
+	if err != nil || restored.Task.Status != StatusTodo || restored.Task.CompletedAt != nil || restored.Topic != "work" {
+		t.Errorf("Expected restored todo task in work, got %+v (%v)", restored, err)
+	}
+
+	if err := fs.DeleteTask(restored); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	if _, err := fs.GetTask(task.ID); err == nil {
+		t.Errorf("Expected deleted task to be gone")
+	}
+}
+
+// TestTaskBodyRoundTrip tests that hand-written Markdown bodies survive saves
+func TestCopyTaskStartsFresh(t *testing.T) {
+	fs := NewFileStore(t.TempDir())
+	created := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	start := created.Add(time.Hour)
+	end := start.Add(time.Hour)
+	task := &Task{Title: "Tracked", Status: StatusInProgress, CreatedAt: created,
+		TimeLog: []TimeEntry{{Start: start, End: &end}, {Start: end.Add(time.Hour)}}}
+	if err := fs.SaveTask("", task); err != nil {
+		t.Fatal(err)
+	}
+	found, _ := fs.GetTask(task.ID)
+	os.WriteFile(found.FilePath, []byte(strings.Replace(fs.taskToMarkdown(found.Task), "---\n", "---\nowner: sam\n", 1)), 0644)
+	found, _ = fs.GetTask(task.ID)
+
+	copied, err := fs.CopyTask(found, "home")
+	if err != nil {
+		t.Fatalf("Failed to copy task: %v", err)
+	}
+	reloaded, err := fs.GetTask(copied.Task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reloaded.Task
+	if got.runningEntry() != nil || len(got.TimeLog) != 1 {
+		t.Errorf("Expected only the finished time entry to be copied, got %+v", got.TimeLog)
+	}
+	if !got.CreatedAt.After(created) || len(got.History) != 1 || got.History[0].From != "" {
+		t.Errorf("Expected the copy to start its own history, got created %v history %+v", got.CreatedAt, got.History)
+	}
+	if !strings.Contains(got.Frontmatter, "owner: sam") {
+		t.Errorf("Expected other frontmatter keys to be kept, got:\n%s", got.Frontmatter)
+	}
+	if original, _ := fs.GetTask(task.ID); original.Task.runningEntry() == nil {
+		t.Errorf("Expected the original's timer to keep running")
+	}
+}
+
+func TestTaskBodyRoundTrip(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "tada-body-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	fs := NewFileStore(tempDir)
+
+	body := "\n# Notes\n\n- [ ] check the logs\n- [x] ping ops\n\nSee https://example.com/runbook\n"
+	content := "---\nid: 0000beef\ntitle: Handwritten\nstatus: todo\ncreated_at: 2025-06-18T10:00:00Z\n---\n" + body
+	path := filepath.Join(tempDir, TasksDir, "handwritten.md")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write task: %v", err)
+	}
+
+	task, err := fs.GetTask("0000beef")
+	if err != nil {
+		t.Fatalf("Failed to load task: %v", err)
+	}
+	if task.Task.Body != body {
+		t.Fatalf("Expected body to be loaded verbatim, got %q", task.Task.Body)
+	}
+
+	task.Task.Status = StatusInProgress
+	if err := fs.UpdateTask(task); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasSuffix(string(data), "---\n"+body) {
+		t.Errorf("Expected body to be preserved byte-for-byte, got:\n%s", data)
+	}
+
+	// Generated bodies follow the title and description
+	generated := &Task{Title: "Generated", Description: "first"}
+	generated.Body = defaultTaskBody(generated)
+	if !generated.hasGeneratedBody() {
+		t.Errorf("Expected default body to be recognised as generated")
+	}
+	if task.Task.hasGeneratedBody() {
+		t.Errorf("Expected hand-written body not to be treated as generated")
+	}
+}
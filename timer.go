@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// runningEntry returns the task's open time entry, or nil.
+func (t *Task) runningEntry() *TimeEntry {
+	for i := range t.TimeLog {
+		if t.TimeLog[i].End == nil {
+			return &t.TimeLog[i]
+		}
+	}
+	return nil
+}
+
+// startTimer opens a time entry at now and marks the task in progress, if
+// the workflow has that status. It does nothing if the task's timer is
+// already running.
+func (t *Task) startTimer(now time.Time) error {
+	if t.runningEntry() != nil {
+		return nil
+	}
+	status, ok := timerStatus()
+	if ok {
+		if err := checkTransition(t.Status, status); err != nil {
+			return err
+		}
+	}
+	t.TimeLog = append(t.TimeLog, TimeEntry{Start: now})
+	if ok {
+		t.setStatus(status)
+	}
+	return nil
+}
+
+// stopTimer closes the running time entry at now and returns its length.
+// ok is false if no timer was running.
+func (t *Task) stopTimer(now time.Time) (elapsed time.Duration, ok bool) {
+	entry := t.runningEntry()
+	if entry == nil {
+		return 0, false
+	}
+	end := now
+	entry.End = &end
+	return end.Sub(entry.Start), true
+}
+
+// timeSpans returns the task's tracked intervals clipped to [from, to). A
+// running interval counts up to now. A zero from or to leaves that side
+// open.
+func (t *Task) timeSpans(from, to, now time.Time) [][2]time.Time {
+	var spans [][2]time.Time
+	for _, e := range t.TimeLog {
+		start, end := e.Start, now
+		if e.End != nil {
+			end = *e.End
+		}
+		if !from.IsZero() && start.Before(from) {
+			start = from
+		}
+		if !to.IsZero() && end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			spans = append(spans, [2]time.Time{start, end})
+		}
+	}
+	return spans
+}
+
+// trackedTime returns the total time tracked on the task, including a
+// running timer.
+func (t *Task) trackedTime(now time.Time) time.Duration {
+	var total time.Duration
+	for _, span := range t.timeSpans(time.Time{}, time.Time{}, now) {
+		total += span[1].Sub(span[0])
+	}
+	return total
+}
+
+// timerRunningError is returned when starting a timer while another task's
+// timer is running.
+type timerRunningError struct {
+	running *TaskWithPath
+}
+
+func (e *timerRunningError) Error() string {
+	return fmt.Sprintf("already tracking %q since %s", e.running.Task.Title, e.running.Task.runningEntry().Start.Format("15:04"))
+}
+
+func (e *timerRunningError) Unwrap() error { return errInvalidInput }
+
+// startTaskTimer starts t's timer at now. It fails with a
+// *timerRunningError if another task's timer is running. Looking for the
+// running timer and writing t happen as one store update, so two tada
+// start runs cannot both start a timer.
+func startTaskTimer(store Storage, t *TaskWithPath, now time.Time) error {
+	return store.UpdateTasks(func(tasks map[string][]*TaskWithPath) ([]*TaskWithPath, error) {
+		if running := runningTimer(tasks); running != nil {
+			return nil, &timerRunningError{running: running}
+		}
+		if err := t.Task.startTimer(now); err != nil {
+			return nil, err
+		}
+		return []*TaskWithPath{t}, nil
+	})
+}
+
+// runningTimer returns the task whose timer is running, or nil. tada
+// start keeps this to at most one task per workspace.
+func runningTimer(tasks map[string][]*TaskWithPath) *TaskWithPath {
+	for _, list := range tasks {
+		for _, t := range list {
+			if t.Task.runningEntry() != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// parseSince parses the start of a reporting period. Weekday names resolve
+// to the most recent such day, today included, so "monday" means the start
+// of this week; spans such as "7d" count back from now. Anything else is
+// parsed by parseDate.
+func parseSince(input string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	today := startOfDay(now)
+	if day, ok := weekdays[strings.TrimPrefix(s, "last ")]; ok {
+		back := (int(today.Weekday()) - int(day) + 7) % 7
+		if strings.HasPrefix(s, "last ") && back == 0 {
+			back = 7
+		}
+		return today.AddDate(0, 0, -back), nil
+	}
+	if t, err := parseAge(s, now); err == nil {
+		return t, nil
+	}
+	return parseDate(s, now)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartStopCommands(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	runWithStore(NewAddCmd(store), "work/Write report")
+	runWithStore(NewAddCmd(store), "home/Fix bike")
+
+	if out := runWithStore(NewStartCmd(store), "work/Write report"); !strings.Contains(out, `Started "Write report"`) {
+		t.Fatalf("Expected the timer to start, got: %s", out)
+	}
+	tasks, _ := store.LoadAllTasks()
+	report := tasks["work"][0].Task
+	if report.Status != StatusInProgress || report.runningEntry() == nil {
+		t.Fatalf("Expected a running in-progress task, got %+v", report)
+	}
+
+	// Only one timer runs per workspace
+	if out := runWithStore(NewStartCmd(store), "home/Fix bike"); !strings.Contains(out, `already tracking "Write report"`) {
+		t.Errorf("Expected the second timer to be refused, got: %s", out)
+	}
+	m := model{tasks: tasks, expanded: map[string]bool{}, store: store}
+	m.buildItems()
+	if view := m.viewList(); !strings.Contains(view, "⏱ Write report") {
+		t.Errorf("Expected the running timer in the TUI header, got:\n%s", view)
+	}
+
+	if out := runWithStore(NewStopCmd(store)); !strings.Contains(out, `Stopped "Write report" after 0m`) {
+		t.Errorf("Expected the timer to stop, got: %s", out)
+	}
+	if out := runWithStore(NewStopCmd(store)); !strings.Contains(out, "No timer is running") {
+		t.Errorf("Expected no running timer, got: %s", out)
+	}
+	tasks, _ = store.LoadAllTasks()
+	if log := tasks["work"][0].Task.TimeLog; len(log) != 1 || log[0].End == nil || tasks["work"][0].Task.Status != StatusInProgress {
+		t.Errorf("Expected one closed interval on an in-progress task, got %+v", tasks["work"][0].Task)
+	}
+
+	// Completing a task stops its timer
+	runWithStore(NewStartCmd(store), "home/Fix bike")
+	runWithStore(NewCompleteCmd(store), "home/Fix bike")
+	archived, _ := store.LoadArchivedTasks()
+	if archived["home"][0].Task.runningEntry() != nil {
+		t.Errorf("Expected completing to stop the timer")
+	}
+	if out := runWithStore(NewStartCmd(store), "work/Write report"); !strings.Contains(out, "Started") {
+		t.Errorf("Expected a new timer after completion, got: %s", out)
+	}
+
+	// The running timer is checked when the timer starts, not when the
+	// tasks were loaded
+	runWithStore(NewAddCmd(store), "home/Paint fence")
+	tasks, _ = store.LoadAllTasks()
+	fence := tasks["home"][0]
+	var running *timerRunningError
+	if err := startTaskTimer(store, fence, time.Now()); !errors.As(err, &running) || exitCode(err) != exitInvalidInput {
+		t.Errorf("Expected a timerRunningError, got %v", err)
+	}
+
+	// Deleting a task stops its timer
+	runWithStore(NewDeleteCmd(store), "work/Write report")
+	trashed, _ := store.LoadTrashedTasks()
+	if trashed["work"][0].Task.runningEntry() != nil {
+		t.Errorf("Expected deleting to stop the timer")
+	}
+	if out := runWithStore(NewStartCmd(store), "home/Paint fence"); !strings.Contains(out, "Started") {
+		t.Errorf("Expected a new timer after deletion, got: %s", out)
+	}
+}
+
+func TestTimesheet(t *testing.T) {
+	at := func(s string) time.Time {
+		v, _ := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		return v
+	}
+	end := func(s string) *time.Time { v := at(s); return &v }
+	now := at("2026-10-15 12:00")
+	tasks := map[string][]*TaskWithPath{
+		"work": {{Topic: "work", Task: &Task{Title: "Report", Tags: []string{"writing", "q3"}, TimeLog: []TimeEntry{
+			{Start: at("2026-10-05 09:00"), End: end("2026-10-05 17:00")}, // before the period
+			{Start: at("2026-10-12 23:00"), End: end("2026-10-13 01:30")},
+			{Start: at("2026-10-15 11:00")}, // running
+		}}}},
+		"": {{Task: &Task{Title: "Errands", TimeLog: []TimeEntry{{Start: at("2026-10-14 10:00"), End: end("2026-10-14 10:45")}}}}},
+	}
+	from, _ := parseSince("monday", now)
+	if !from.Equal(at("2026-10-12 00:00")) {
+		t.Fatalf("Expected monday to be the start of the week, got %v", from)
+	}
+
+	rows, total := timesheet([]map[string][]*TaskWithPath{tasks}, "day", from, now)
+	var days []string
+	for _, r := range rows {
+		days = append(days, r.Group+"="+formatDuration(r.total))
+	}
+	if got := strings.Join(days, " "); got != "2026-10-12=1h 0m 2026-10-13=1h 30m 2026-10-14=45m 2026-10-15=1h 0m" {
+		t.Errorf("Unexpected days: %s", got)
+	}
+	if total != 4*time.Hour+15*time.Minute {
+		t.Errorf("Expected 4h 15m in total, got %v", total)
+	}
+
+	rows, total = timesheet([]map[string][]*TaskWithPath{tasks}, "tag", from, now)
+	if len(rows) != 3 || rows[0].Hours != 3.5 || rows[2].Group != "-" || rows[2].Hours != 0.75 || total != 4*time.Hour+15*time.Minute {
+		t.Errorf("Expected each tag to count the task once and the total once, got %+v", rows)
+	}
+
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{Title: "Report", Status: StatusTodo, TimeLog: tasks["work"][0].Task.TimeLog[:2]})
+	var parsed []timesheetRow
+	out := runWithStore(NewTimesheetCmd(store), "--since", "2026-10-01", "-o", "json")
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil || len(parsed) != 1 || parsed[0].Group != "work" || parsed[0].Seconds != 10.5*3600 {
+		t.Errorf("Unexpected JSON timesheet (%v): %s", err, out)
+	}
+	if out := runWithStore(NewTimesheetCmd(store), "--since", "2026-10-01", "--by", "day", "-o", "csv"); !strings.HasPrefix(out, "Day,Seconds,Hours,Tasks\n2026-10-05,28800,8.00,1\n") {
+		t.Errorf("Unexpected CSV timesheet: %s", out)
+	}
+	if out := runWithStore(NewTimesheetCmd(store), "--by", "week"); !strings.Contains(out, "Invalid --by") {
+		t.Errorf("Expected an invalid grouping error, got: %s", out)
+	}
+}
@@ -3,31 +3,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-
-	"gopkg.in/yaml.v3"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// UndoActionType represents the type of action that can be undone.
-type UndoActionType int
-
-const (
-	UndoNone UndoActionType = iota
-	UndoDelete
-	UndoComplete
-)
-
-type UndoEntry struct {
-	Action UndoActionType
-	Task   *TaskWithPath
-}
-
 type viewMode int
 
 const (
@@ -42,24 +28,30 @@ type model struct {
 	items    []item
 	selected int
 	expanded map[string]bool
-	mode     viewMode
-	editTask *TaskWithPath
-	editForm form
-	err      error
-	height   int // track terminal height
+	// expandedTasks holds the keys (see taskKey) of tasks whose checklist is shown
+	expandedTasks map[string]bool
+	mode          viewMode
+	editTask      *TaskWithPath
+	editForm      form
+	err           error
+	height        int // track terminal height
 	// QoL features
-	yankedTask    *TaskWithPath
-	searchQuery   string
-	searchMode    bool
-	showDetails   bool
-	toArchive     []*TaskWithPath // tasks to archive on exit
-	store         *FileStore      // injected for testability, optional
-	confirmDelete bool            // show confirm dialog
-	pendingDelete *TaskWithPath   // task to delete if confirmed
-
-	// Undo stack (single-level for now)
-	undoStack []UndoEntry
-	undoMsg   string // status message for undo
+	yankedTask   *TaskWithPath
+	searchQuery  string
+	searchMode   bool
+	searchErr    string // why searchQuery does not parse, shown in the search bar
+	showDetails  bool
+	showArchived bool // list archived tasks instead of active ones
+	// diskFingerprint is the last seen store fingerprint, for live reload
+	diskFingerprint string
+	editWarning     string          // shown in the edit view when the file changed underneath
+	toArchive       []*TaskWithPath // tasks to archive on exit
+	store           Storage         // injected for testability, optional
+	confirmDelete   bool            // show confirm dialog
+	pendingDelete   *TaskWithPath   // task to delete if confirmed
+
+	// Undo and redo go through the store's journal
+	undoMsg string // status message for undo
 
 	// Bulk selection support
 	selectedItems map[int]struct{} // index-based selection for bulk actions
@@ -68,6 +60,11 @@ type model struct {
 	// Export prompt state
 	exportPrompt *exportPromptState // nil unless prompting for export
 	exportMsg    string             // status message for export
+
+	// Saved views from the config; tab switches between them
+	views     map[string]View
+	viewNames []string
+	view      string // the active view, or "" for all tasks
 }
 
 type item struct {
@@ -75,6 +72,9 @@ type item struct {
 	isTopic bool
 	topic   string
 	task    *TaskWithPath
+	// isCheck marks a checklist row of task; checkIndex is the item's index
+	isCheck    bool
+	checkIndex int
 }
 
 type form struct {
@@ -84,8 +84,25 @@ type form struct {
 	priority string
 	status   TaskStatus
 	tags     string
+	body     string
+	// fields holds custom field values by name; their form indices follow
+	// numFormFields in config order.
+	fields map[string]string
 }
 
+// Form field indices, in tab order. The body field is only shown when editing.
+const (
+	fieldTitle = iota
+	fieldDesc
+	fieldPriority
+	fieldStatus
+	fieldTags
+	fieldBody
+	fieldSave
+	fieldCancel
+	numFormFields
+)
+
 type exportPromptState struct {
 	step     int // 0: format, 1: path
 	format   string
@@ -103,6 +120,7 @@ var (
 	selectedStyle = lipgloss.NewStyle().Reverse(true)
 	topicStyle    = lipgloss.NewStyle().Foreground(accent).Bold(true)
 	focusStyle    = lipgloss.NewStyle().Foreground(warning).Bold(true)
+	overdueStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
 )
 
 func initialModel() model {
@@ -115,12 +133,23 @@ func initialModel() model {
 }
 
 func (m model) Init() tea.Cmd {
-	return loadTasks
+	return tea.Batch(m.loadTasks, m.watchTasks())
+}
+
+// storage returns the injected store, falling back to the default .tada directory.
+func (m model) storage() Storage {
+	if m.store != nil {
+		return m.store
+	}
+	return NewFileStore()
 }
 
-func loadTasks() tea.Msg {
-	store := NewFileStore()
-	tasks, err := store.LoadAllTasks()
+func (m model) loadTasks() tea.Msg {
+	load := m.storage().LoadAllTasks
+	if m.showArchived {
+		load = m.storage().LoadArchivedTasks
+	}
+	tasks, err := load()
 	return struct {
 		tasks map[string][]*TaskWithPath
 		err   error
@@ -140,12 +169,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.buildItems()
 				return m, nil
 			}
+			if msg.String() == "enter" {
+				// Keep the filter and go back to the list
+				m.searchMode = false
+				return m, nil
+			}
 			if msg.String() == "backspace" && len(m.searchQuery) > 0 {
-				m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
+				m.setSearchQuery(m.searchQuery[:len(m.searchQuery)-1])
 				return m, nil
 			}
 			if len(msg.String()) == 1 {
-				m.searchQuery += msg.String()
+				m.setSearchQuery(m.searchQuery + msg.String())
 				return m, nil
 			}
 		}
@@ -158,32 +192,77 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.height = msg.Height
 		return m, nil
+	case watchTickMsg:
+		return m.checkForChanges()
 	case struct {
 		tasks map[string][]*TaskWithPath
 		err   error
 	}:
+		var selectedKey string
+		if m.selected < len(m.items) {
+			selectedKey = itemKey(m.items[m.selected])
+		}
 		m.tasks = msg.tasks
 		m.err = msg.err
 		m.buildItems()
+		m.restoreSelection(selectedKey)
+		m.checkEditConflict()
 		return m, nil
 	}
 	return m, nil
 }
 
+// stepJournal undoes or redoes the last change through the store's
+// journal, which also covers changes made outside the TUI.
+func (m model) stepJournal(undo bool) (tea.Model, tea.Cmd) {
+	j, ok := m.storage().(journal)
+	if !ok {
+		m.undoMsg = "Undo is not available."
+		return m, nil
+	}
+	var entry *journalEntry
+	var err error
+	if undo {
+		entry, err = j.Undo()
+	} else {
+		entry, err = j.Redo()
+	}
+	switch {
+	case errors.Is(err, errNothingToUndo):
+		m.undoMsg = "Nothing to undo."
+		return m, nil
+	case errors.Is(err, errNothingToRedo):
+		m.undoMsg = "Nothing to redo."
+		return m, nil
+	case err != nil && undo:
+		m.undoMsg = "Undo failed: " + err.Error()
+		return m, m.loadTasks
+	case err != nil:
+		m.undoMsg = "Redo failed: " + err.Error()
+		return m, m.loadTasks
+	case undo:
+		m.undoMsg = "Undo: restored the state before " + entry.Summary + ". Press ctrl+r to redo."
+	default:
+		m.undoMsg = "Redo: " + entry.Summary + " applied again."
+	}
+	return m, m.loadTasks
+}
+
 // updateListView handles key events and actions in the main list view.
 func (m model) updateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.confirmDelete {
 		switch msg.String() {
 		case "y":
 			if m.pendingDelete != nil {
-				// Save undo info before deleting
-				m.undoStack = append(m.undoStack, UndoEntry{Action: UndoDelete, Task: m.pendingDelete})
-				_ = os.Remove(m.pendingDelete.FilePath)
-				m.undoMsg = "Task deleted. Press 'u' to undo."
+				if err := m.storage().DeleteTask(m.pendingDelete); err != nil {
+					m.undoMsg = "Not deleted: " + err.Error()
+				} else {
+					m.undoMsg = "Task moved to trash. Press 'u' to undo."
+				}
 			}
 			m.confirmDelete = false
 			m.pendingDelete = nil
-			return m, loadTasks
+			return m, m.loadTasks
 		case "n", "esc":
 			m.confirmDelete = false
 			m.pendingDelete = nil
@@ -193,22 +272,68 @@ func (m model) updateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if m.showArchived {
+		switch msg.String() {
+		case "ctrl+c", "q", "/", "tab", "shift+tab", "i", "j", "k", "up", "down", "r", "A", "u", "ctrl+r":
+			// Read-only keys are handled below
+		case "R":
+			if m.selected < len(m.items) && m.items[m.selected].task != nil {
+				task := m.items[m.selected].task
+				if err := m.storage().RestoreTask(task); err != nil {
+					m.undoMsg = "Restore failed: " + err.Error()
+					return m, nil
+				}
+				m.undoMsg = "Task restored: " + task.Task.Title
+				return m, m.loadTasks
+			}
+			return m, nil
+		case " ", "space", "enter":
+			if m.selected < len(m.items) && m.items[m.selected].isTopic {
+				topic := m.items[m.selected].topic
+				m.expanded[topic] = !m.expanded[topic]
+				m.buildItems()
+			}
+			return m, nil
+		default:
+			// Archived tasks are not edited in place; restore them first
+			return m, nil
+		}
+	}
+
 	switch msg.String() {
+	case "A":
+		m.showArchived = !m.showArchived
+		m.selected = 0
+		m.selectedItems = make(map[int]struct{})
+		m.showDetails = false
+		return m, m.loadTasks
 	case "ctrl+c", "q":
-		// Archive any completed tasks before quitting
+		// Archive any completed tasks before quitting, skipping those an
+		// undo has reopened since
 		if len(m.toArchive) > 0 {
+			store := m.storage()
 			for _, task := range m.toArchive {
-				store := m.store
-				if store == nil {
-					store = NewFileStore()
+				if task.Task.ID != "" {
+					current, err := store.GetTask(task.Task.ID)
+					if err != nil || !current.Task.Status.isClosed() {
+						continue
+					}
+					task = current
 				}
-				_ = store.CompleteTask(task.Topic, task.Task.Title)
+				_, _ = completeTask(store, task)
 			}
 		}
 		return m, tea.Quit
 	case "/":
 		m.searchMode = true
-		m.searchQuery = ""
+		m.view = ""
+		m.setSearchQuery("")
+		return m, nil
+	case "tab":
+		m.cycleView(1)
+		return m, nil
+	case "shift+tab":
+		m.cycleView(-1)
 		return m, nil
 	case "i":
 		if m.showDetails {
@@ -223,31 +348,27 @@ func (m model) updateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "p":
 		if m.yankedTask != nil {
-			copy := *m.yankedTask
-			orig := m.yankedTask.Task
-			copy.Task = &Task{
-				Title:       orig.Title + " (Copy)",
-				Description: orig.Description,
-				Status:      orig.Status,
-				Priority:    orig.Priority,
-				Tags:        append([]string{}, orig.Tags...),
+			if _, err := m.storage().CopyTask(m.yankedTask, m.yankedTask.Topic); err != nil {
+				m.undoMsg = "Not pasted: " + err.Error()
+			} else {
+				m.undoMsg = "Task pasted. Press 'u' to undo."
 			}
-			store := NewFileStore()
-			_ = store.SaveTask(copy.Topic, copy.Task)
-			return m, loadTasks
+			return m, m.loadTasks
 		}
 	case "d":
 		if len(m.selectedItems) > 0 {
-			// Bulk delete
-			for idx := range m.selectedItems {
-				if idx < len(m.items) && m.items[idx].task != nil {
-					_ = os.Remove(m.items[idx].task.FilePath)
-					m.undoStack = append(m.undoStack, UndoEntry{Action: UndoDelete, Task: m.items[idx].task})
+			// Bulk delete, undone as one change
+			journaled(m.storage(), fmt.Sprintf("delete %d tasks", len(m.selectedItems)), func() error {
+				for idx := range m.selectedItems {
+					if idx < len(m.items) && m.items[idx].task != nil {
+						_ = m.storage().DeleteTask(m.items[idx].task)
+					}
 				}
-			}
-			m.undoMsg = "Bulk delete complete. Press 'u' to undo last."
+				return nil
+			})
+			m.undoMsg = "Bulk delete complete. Press 'u' to undo."
 			m.selectedItems = make(map[int]struct{})
-			return m, loadTasks
+			return m, m.loadTasks
 		}
 		if m.selected < len(m.items) && m.items[m.selected].task != nil {
 			m.confirmDelete = true
@@ -263,34 +384,35 @@ func (m model) updateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "s":
 		if len(m.selectedItems) > 0 {
-			for idx := range m.selectedItems {
-				if idx < len(m.items) && m.items[idx].task != nil {
-					task := m.items[idx].task
-					m.cycleTaskStatus(task, 1)
-					if task.Task.Status == StatusDone {
-						m.toArchive = append(m.toArchive, task)
-						m.undoStack = append(m.undoStack, UndoEntry{Action: UndoComplete, Task: task})
+			journaled(m.storage(), fmt.Sprintf("change status of %d tasks", len(m.selectedItems)), func() error {
+				for idx := range m.selectedItems {
+					if idx < len(m.items) && m.items[idx].task != nil {
+						task := m.items[idx].task
+						m.cycleTaskStatus(task, 1)
+						if task.Task.Status.isClosed() {
+							m.toArchive = append(m.toArchive, task)
+						}
 					}
 				}
-			}
-			m.undoMsg = "Bulk status cycle complete. Press 'u' to undo last."
-			return m, loadTasks
+				return nil
+			})
+			m.undoMsg = "Bulk status cycle complete. Press 'u' to undo."
+			return m, m.loadTasks
 		}
 		if m.selected < len(m.items) && m.items[m.selected].task != nil {
 			task := m.items[m.selected].task
 			m.cycleTaskStatus(task, 1)
-			if task.Task.Status == StatusDone {
+			if task.Task.Status.isClosed() {
 				m.toArchive = append(m.toArchive, task)
-				m.undoStack = append(m.undoStack, UndoEntry{Action: UndoComplete, Task: task})
 				m.undoMsg = "Task completed. Press 'u' to undo."
 			}
-			return m, loadTasks
+			return m, m.loadTasks
 		}
 	case "S":
 		if m.selected < len(m.items) && m.items[m.selected].task != nil {
 			task := m.items[m.selected].task
 			m.cycleTaskStatus(task, -1)
-			return m, loadTasks
+			return m, m.loadTasks
 		}
 	case "j", "down":
 		if m.selected < len(m.items)-1 {
@@ -300,12 +422,23 @@ func (m model) updateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.selected > 0 {
 			m.selected--
 		}
-	case "space", "enter":
+	case " ", "space", "enter":
+		// Bubble Tea reports the space bar as " "
 		if m.selected < len(m.items) {
 			item := m.items[m.selected]
 			if item.isTopic {
 				m.expanded[item.topic] = !m.expanded[item.topic]
 				m.buildItems()
+			} else if item.isCheck {
+				cmd := m.toggleChecklistItem(item)
+				return m, cmd
+			} else if item.task != nil && msg.String() != "enter" && item.task.Task.progress() != "" {
+				if m.expandedTasks == nil {
+					m.expandedTasks = make(map[string]bool)
+				}
+				key := taskKey(item.task)
+				m.expandedTasks[key] = !m.expandedTasks[key]
+				m.buildItems()
 			} else if item.task != nil {
 				m.mode = editView
 				m.editTask = item.task
@@ -326,40 +459,11 @@ func (m model) updateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = addView
 		m.initAddFormWithTopic(topic)
 	case "r":
-		return m, loadTasks
+		return m, m.loadTasks
 	case "u":
-		if len(m.undoStack) > 0 {
-			entry := m.undoStack[len(m.undoStack)-1]
-			m.undoStack = m.undoStack[:len(m.undoStack)-1]
-			switch entry.Action {
-			case UndoDelete:
-				// Restore deleted task at original file path
-				if entry.Task != nil && entry.Task.FilePath != "" && entry.Task.Task != nil {
-					content := "---\n"
-					if entry.Task.Task != nil {
-						data, _ := yaml.Marshal(entry.Task.Task)
-						content += string(data)
-					}
-					content += "---\n\n# " + entry.Task.Task.Title + "\n"
-					_ = os.WriteFile(entry.Task.FilePath, []byte(content), 0644)
-					m.undoMsg = "Undo: Task restored."
-					return m, loadTasks
-				}
-				m.undoMsg = "Undo failed."
-				return m, nil
-			case UndoComplete:
-				// Mark as not completed
-				entry.Task.Task.Status = StatusTodo
-				entry.Task.Task.CompletedAt = nil
-				store := m.store
-				if store == nil {
-					store = NewFileStore()
-				}
-				_ = store.SaveTask(entry.Task.Topic, entry.Task.Task)
-				m.undoMsg = "Undo: Task marked as not completed."
-				return m, loadTasks
-			}
-		}
+		return m.stepJournal(true)
+	case "ctrl+r":
+		return m.stepJournal(false)
 	case "x":
 		if len(m.selectedItems) > 0 {
 			m.exportPrompt = &exportPromptState{step: 0}
@@ -397,9 +501,10 @@ func (m model) updateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.lastSelect = -1
 			return m, nil
 		}
-		if m.searchMode {
+		if m.searchMode || m.searchQuery != "" || m.view != "" {
 			m.searchMode = false
 			m.searchQuery = ""
+			m.view = ""
 			m.buildItems()
 			return m, nil
 		}
@@ -418,42 +523,24 @@ func (m model) updateEditView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = listView
 		return m, nil
 	case "tab":
-		m.editForm.field = (m.editForm.field + 1) % 7
+		m.nextField(1)
 	case "shift+tab":
-		m.editForm.field = (m.editForm.field - 1 + 7) % 7
+		m.nextField(-1)
 	case "enter":
-		if m.editForm.field == 5 { // Save
+		if m.editForm.field == fieldSave {
 			return m.saveTask()
-		} else if m.editForm.field == 6 { // Cancel
+		} else if m.editForm.field == fieldCancel {
 			m.mode = listView
 			return m, nil
+		} else if m.editForm.field == fieldBody {
+			m.editText("\n")
 		}
-	case "left":
-		if m.editForm.field == 3 {
-			m.cycleStatus(-1)
-		} else if m.editForm.field == 2 {
-			m.cyclePriority(-1)
-		}
-	case "right":
-		if m.editForm.field == 3 {
-			m.cycleStatus(1)
-		} else if m.editForm.field == 2 {
-			m.cyclePriority(1)
-		}
-	case "h":
-		if m.editForm.field == 3 {
-			m.cycleStatus(-1)
-		} else if m.editForm.field == 2 {
-			m.cyclePriority(-1)
-		} else {
+	case "left", "h":
+		if !m.cycleField(-1) && msg.String() == "h" {
 			m.editText("h")
 		}
-	case "l":
-		if m.editForm.field == 3 {
-			m.cycleStatus(1)
-		} else if m.editForm.field == 2 {
-			m.cyclePriority(1)
-		} else {
+	case "right", "l":
+		if !m.cycleField(1) && msg.String() == "l" {
 			m.editText("l")
 		}
 	case "backspace":
@@ -475,42 +562,22 @@ func (m model) updateAddView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = listView
 		return m, nil
 	case "tab":
-		m.editForm.field = (m.editForm.field + 1) % 7
+		m.nextField(1)
 	case "shift+tab":
-		m.editForm.field = (m.editForm.field - 1 + 7) % 7
+		m.nextField(-1)
 	case "enter":
-		if m.editForm.field == 5 { // Save
+		if m.editForm.field == fieldSave {
 			return m.addTask()
-		} else if m.editForm.field == 6 { // Cancel
+		} else if m.editForm.field == fieldCancel {
 			m.mode = listView
 			return m, nil
 		}
-	case "left":
-		if m.editForm.field == 3 {
-			m.cycleStatus(-1)
-		} else if m.editForm.field == 2 {
-			m.cyclePriority(-1)
-		}
-	case "right":
-		if m.editForm.field == 3 {
-			m.cycleStatus(1)
-		} else if m.editForm.field == 2 {
-			m.cyclePriority(1)
-		}
-	case "h":
-		if m.editForm.field == 3 {
-			m.cycleStatus(-1)
-		} else if m.editForm.field == 2 {
-			m.cyclePriority(-1)
-		} else {
+	case "left", "h":
+		if !m.cycleField(-1) && msg.String() == "h" {
 			m.editText("h")
 		}
-	case "l":
-		if m.editForm.field == 3 {
-			m.cycleStatus(1)
-		} else if m.editForm.field == 2 {
-			m.cyclePriority(1)
-		} else {
+	case "right", "l":
+		if !m.cycleField(1) && msg.String() == "l" {
 			m.editText("l")
 		}
 	case "backspace":
@@ -525,46 +592,116 @@ func (m model) updateAddView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// formOrder lists the form's field indices in tab order: custom fields
+// come after tags, and the body is only shown when editing.
+func (m *model) formOrder() []int {
+	order := []int{fieldTitle, fieldDesc, fieldPriority, fieldStatus, fieldTags}
+	for i := range customFields {
+		order = append(order, numFormFields+i)
+	}
+	if m.mode != addView {
+		order = append(order, fieldBody)
+	}
+	return append(order, fieldSave, fieldCancel)
+}
+
+// nextField moves focus by direction through the form's tab order.
+func (m *model) nextField(direction int) {
+	order := m.formOrder()
+	current := 0
+	for i, field := range order {
+		if field == m.editForm.field {
+			current = i
+		}
+	}
+	m.editForm.field = order[(current+direction+len(order))%len(order)]
+}
+
+// customField returns the custom field that has focus, if any.
+func (m *model) customField() (FieldDef, bool) {
+	i := m.editForm.field - numFormFields
+	if i < 0 || i >= len(customFields) {
+		return FieldDef{}, false
+	}
+	return customFields[i], true
+}
+
+// cycleField changes the focused field by direction if it takes one of a
+// fixed set of values, reporting whether it did.
+func (m *model) cycleField(direction int) bool {
+	switch m.editForm.field {
+	case fieldStatus:
+		m.cycleStatus(direction)
+		return true
+	case fieldPriority:
+		m.cyclePriority(direction)
+		return true
+	}
+	f, ok := m.customField()
+	if !ok || f.choices() == nil {
+		return false
+	}
+	// Cycling includes the empty value, which clears the field
+	choices := append([]string{""}, f.choices()...)
+	current := choiceIndex(choices, m.editForm.fields[f.Name]) % len(choices)
+	m.editForm.fields[f.Name] = choices[(current+direction+len(choices))%len(choices)]
+	return true
+}
+
 func (m *model) editText(char string) {
 	switch m.editForm.field {
-	case 0: // Title
+	case fieldTitle:
 		if char == "" && len(m.editForm.title) > 0 {
 			m.editForm.title = m.editForm.title[:len(m.editForm.title)-1]
 		} else {
 			m.editForm.title += char
 		}
-	case 1: // Description
+	case fieldDesc:
 		if char == "" && len(m.editForm.desc) > 0 {
 			m.editForm.desc = m.editForm.desc[:len(m.editForm.desc)-1]
 		} else {
 			m.editForm.desc += char
 		}
-	case 2: // Priority
+	case fieldPriority:
 		if char == "" && len(m.editForm.priority) > 0 {
 			m.editForm.priority = m.editForm.priority[:len(m.editForm.priority)-1]
 		} else if char >= "0" && char <= "9" {
 			m.editForm.priority += char
 		}
-	case 4: // Tags
+	case fieldTags:
 		if char == "" && len(m.editForm.tags) > 0 {
 			m.editForm.tags = m.editForm.tags[:len(m.editForm.tags)-1]
 		} else {
 			m.editForm.tags += char
 		}
+	case fieldBody:
+		if char == "" && len(m.editForm.body) > 0 {
+			m.editForm.body = m.editForm.body[:len(m.editForm.body)-1]
+		} else {
+			m.editForm.body += char
+		}
+	default:
+		f, ok := m.customField()
+		if !ok || f.choices() != nil {
+			return
+		}
+		value := m.editForm.fields[f.Name]
+		if char == "" && len(value) > 0 {
+			m.editForm.fields[f.Name] = value[:len(value)-1]
+		} else {
+			m.editForm.fields[f.Name] = value + char
+		}
 	}
 }
 
+// cycleStatus steps the form's status through the workflow, offering only
+// statuses the edited task may move to.
 func (m *model) cycleStatus(direction int) {
-	statuses := []TaskStatus{StatusTodo, StatusInProgress, StatusDone, StatusPaused, StatusCancelled}
-	current := 0
-	for i, status := range statuses {
-		if status == m.editForm.status {
-			current = i
-			break
-		}
+	var from TaskStatus
+	if m.mode == editView && m.editTask != nil {
+		from = m.editTask.Task.Status
 	}
-	current = (current + direction + len(statuses)) % len(statuses)
-	m.editForm.status = statuses[current]
+	m.editForm.status = nextStatus(m.editForm.status, from, direction)
 }
 
 func (m *model) cyclePriority(direction int) {
@@ -585,25 +722,36 @@ func (m *model) cyclePriority(direction int) {
 
 func (m *model) initForm() {
 	task := m.editTask.Task
+	m.editWarning = ""
 	m.editForm = form{
-		field:    0,
+		field:    fieldTitle,
 		title:    task.Title,
 		desc:     task.Description,
 		priority: fmt.Sprintf("%d", task.Priority),
 		status:   task.Status,
 		tags:     strings.Join(task.Tags, ", "),
+		body:     task.Body,
+		fields:   make(map[string]string),
+	}
+	for key, value := range task.Fields {
+		m.editForm.fields[key] = value
+	}
+	if m.editForm.body == "" {
+		m.editForm.body = defaultTaskBody(task)
 	}
 }
 
 func (m *model) initAddFormWithTopic(topic string) {
 	m.editForm = form{
-		field:    0,
+		field:    fieldTitle,
 		title:    "",
 		desc:     "",
 		priority: "3",
-		status:   StatusTodo,
+		status:   initialStatus(),
 		tags:     "",
+		fields:   make(map[string]string),
 	}
+	m.editWarning = ""
 	if topic != "" {
 		m.editForm.title = topic + "/"
 	}
@@ -612,14 +760,28 @@ func (m *model) initAddFormWithTopic(topic string) {
 // saveTask saves the current task edits to the file.
 func (m model) saveTask() (tea.Model, tea.Cmd) {
 	task := m.editTask.Task
+	fields, err := m.formFields()
+	if err != nil {
+		m.editWarning = "Not saved: " + err.Error()
+		return m, nil
+	}
+	// Check the status before changing anything, so a rejected edit leaves
+	// the task as it was
+	if err := checkTransition(task.Status, m.editForm.status); err != nil {
+		m.undoMsg = "Not saved: " + err.Error()
+		m.mode = listView
+		return m, m.loadTasks
+	}
+	wasClosed := task.Status.isClosed()
+	bodyEdited := m.editForm.body != task.Body && m.editForm.body != defaultTaskBody(task)
+	generated := task.hasGeneratedBody()
 	task.Title = m.editForm.title
 	task.Description = m.editForm.desc
 
 	if m.editForm.priority != "" {
 		fmt.Sscanf(m.editForm.priority, "%d", &task.Priority)
 	}
-
-	task.Status = m.editForm.status
+	task.setStatus(m.editForm.status)
 
 	if m.editForm.tags != "" {
 		tags := strings.Split(m.editForm.tags, ",")
@@ -630,16 +792,36 @@ func (m model) saveTask() (tea.Model, tea.Cmd) {
 	} else {
 		task.Tags = []string{}
 	}
+	task.setFieldValues(fields)
 
-	store := NewFileStore()
-	content := store.taskToMarkdown(task)
+	if bodyEdited {
+		task.Body = m.editForm.body
+	} else if generated {
+		// Regenerate the heading and description; hand-written bodies are kept
+		task.Body = ""
+	}
 
-	if err := os.WriteFile(m.editTask.FilePath, []byte(content), 0644); err != nil {
+	if err := m.storage().UpdateTask(m.editTask); errors.Is(err, errConflict) {
+		m.undoMsg = "Not saved: " + err.Error()
+	} else if err != nil {
 		m.err = fmt.Errorf("failed to save: %w", err)
+	} else if !wasClosed && task.Status.isClosed() {
+		// Closed in the form: archived on exit, like the s key
+		m.toArchive = append(m.toArchive, m.editTask)
+		m.undoMsg = "Task completed. Press 'u' to undo."
 	}
 
 	m.mode = listView
-	return m, loadTasks
+	return m, m.loadTasks
+}
+
+// formFields validates the declared custom fields entered in the form.
+func (m model) formFields() (map[string]string, error) {
+	raw := make(map[string]string)
+	for _, f := range customFields {
+		raw[f.Name] = m.editForm.fields[f.Name]
+	}
+	return normalizeFields(raw, time.Now())
 }
 
 // addTask adds a new task from the add view form.
@@ -648,6 +830,11 @@ func (m model) addTask() (tea.Model, tea.Cmd) {
 		m.err = fmt.Errorf("title cannot be empty")
 		return m, nil
 	}
+	fields, err := m.formFields()
+	if err != nil {
+		m.editWarning = "Not added: " + err.Error()
+		return m, nil
+	}
 	title := m.editForm.title
 	var topic, taskTitle string
 	if strings.Contains(title, "/") {
@@ -681,33 +868,74 @@ func (m model) addTask() (tea.Model, tea.Cmd) {
 		}
 		task.Tags = tags
 	}
+	task.setFieldValues(fields)
 
-	store := NewFileStore()
-	if err := store.SaveTask(topic, task); err != nil {
+	if err := m.storage().SaveTask(topic, task); err != nil {
 		m.err = fmt.Errorf("error adding task: %v", err)
 		return m, nil
 	}
+	if task.Status.isClosed() {
+		// Added as closed: archived on exit, like the s key
+		if added, err := m.storage().GetTask(task.ID); err == nil {
+			m.toArchive = append(m.toArchive, added)
+		}
+	}
 
 	m.mode = listView
-	return m, loadTasks
+	return m, m.loadTasks
 }
 
 // Cycles the status of a given task (for list view status cycling)
 func (m *model) cycleTaskStatus(task *TaskWithPath, direction int) {
-	statuses := []TaskStatus{StatusTodo, StatusInProgress, StatusDone, StatusPaused, StatusCancelled}
-	current := 0
-	for i, status := range statuses {
-		if status == task.Task.Status {
-			current = i
-			break
-		}
+	next := nextStatus(task.Task.Status, task.Task.Status, direction)
+	if next == task.Task.Status {
+		m.undoMsg = fmt.Sprintf("No status change allowed from %s", next)
+		return
 	}
-	current = (current + direction + len(statuses)) % len(statuses)
-	task.Task.Status = statuses[current]
+	previous, history, timeLog := task.Task.Status, task.Task.History, append([]TimeEntry(nil), task.Task.TimeLog...)
+	task.Task.setStatus(next)
 	// Save the updated status to the original file path
-	store := NewFileStore()
-	content := store.taskToMarkdown(task.Task)
-	_ = os.WriteFile(task.FilePath, []byte(content), 0644)
+	if err := m.storage().UpdateTask(task); err != nil {
+		// Typically another process changed the file; the reload shows its version
+		task.Task.Status, task.Task.History, task.Task.TimeLog = previous, history, timeLog
+		m.undoMsg = "Not saved: " + err.Error()
+	}
+}
+
+// setSearchQuery changes the search and refilters, keeping the cursor on
+// the same row when it is still shown.
+func (m *model) setSearchQuery(query string) {
+	var selectedKey string
+	if m.selected < len(m.items) {
+		selectedKey = itemKey(m.items[m.selected])
+	}
+	m.searchQuery = query
+	m.buildItems()
+	m.restoreSelection(selectedKey)
+}
+
+// cycleView switches to the next or, with step -1, the previous saved view.
+// After the last view comes the list of all tasks.
+func (m *model) cycleView(step int) {
+	if len(m.viewNames) == 0 {
+		m.undoMsg = "No saved views. Save one with: tada view save <name> <query>"
+		return
+	}
+	i := 0 // 0 is all tasks, i is viewNames[i-1]
+	for j, name := range m.viewNames {
+		if name == m.view {
+			i = j + 1
+		}
+	}
+	n := len(m.viewNames) + 1
+	i = ((i+step)%n + n) % n
+	m.view, m.searchMode = "", false
+	query := ""
+	if i > 0 {
+		m.view = m.viewNames[i-1]
+		query = m.views[m.view].Query
+	}
+	m.setSearchQuery(query)
 }
 
 // buildItems constructs the visible list of items for the current state.
@@ -724,34 +952,54 @@ func (m *model) buildItems() {
 		return false
 	}
 
+	byID := indexByID(m.tasks)
+
+	// The search bar takes the queries of tada list --search; while one
+	// applies, topics are open to show their matches
+	visible, searching := m.tasks, false
+	m.searchErr = ""
+	if m.searchQuery != "" {
+		if q, err := parseQuery(m.searchQuery, time.Now()); err != nil {
+			m.searchErr = err.Error()
+		} else {
+			visible, searching = q.filter(m.tasks), true
+		}
+	}
+	if sortBy := m.views[m.view].Sort; sortBy != "" {
+		sorted := make(map[string][]*TaskWithPath, len(visible))
+		for topic, tasks := range visible {
+			tasks = append([]*TaskWithPath(nil), tasks...)
+			sortTasks(tasks, sortBy)
+			sorted[topic] = tasks
+		}
+		visible = sorted
+	}
+
 	// Add topics first (excluding root)
-	for topic, tasks := range m.tasks {
+	for topic, tasks := range visible {
 		if topic != "" {
-			m.addTopic(topic, tasks)
+			m.addTopic(topic, tasks, byID, m.expanded[topic] || searching)
 		}
 	}
 
 	// Add root tasks directly (not under a 'Root' group)
-	if tasks, exists := m.tasks[""]; exists {
+	if tasks, exists := visible[""]; exists {
 		for _, task := range tasks {
-			if task.Task.Status == StatusDone && !inToArchive(task) {
+			if task.Task.Status.isClosed() && !m.showArchived && !inToArchive(task) {
 				continue // hide completed tasks unless just completed
 			}
-			title := task.Task.Title
-			if task.Task.Priority != 3 {
-				title = fmt.Sprintf("[%d] %s", task.Task.Priority, title)
-			}
 			m.items = append(m.items, item{
-				text:    getStatusIcon(task.Task.Status) + " " + title,
+				text:    taskLabel(task.Task, byID),
 				isTopic: false,
 				topic:   "",
 				task:    task,
 			})
+			m.addChecklistItems(task, "", "  ")
 		}
 	}
 }
 
-func (m *model) addTopic(topic string, tasks []*TaskWithPath) {
+func (m *model) addTopic(topic string, tasks []*TaskWithPath, byID map[string]*TaskWithPath, expand bool) {
 	name := topic
 	if name == "" {
 		name = "Root"
@@ -772,43 +1020,103 @@ func (m *model) addTopic(topic string, tasks []*TaskWithPath) {
 		return false
 	}
 
-	if m.expanded[topic] {
+	if expand {
 		for _, task := range tasks {
-			if task.Task.Status == StatusDone && !inToArchive(task) {
+			if task.Task.Status.isClosed() && !m.showArchived && !inToArchive(task) {
 				continue // hide completed tasks unless just completed
 			}
-			title := task.Task.Title
-			if task.Task.Priority != 3 {
-				title = fmt.Sprintf("[%d] %s", task.Task.Priority, title)
-			}
-
 			m.items = append(m.items, item{
-				text:    "  " + getStatusIcon(task.Task.Status) + " " + title,
+				text:    "  " + taskLabel(task.Task, byID),
 				isTopic: false,
 				topic:   topic,
 				task:    task,
 			})
+			m.addChecklistItems(task, topic, "    ")
 		}
 	}
 }
 
-func getStatusIcon(status TaskStatus) string {
-	switch status {
-	case StatusTodo:
-		return "○"
-	case StatusInProgress:
-		return "◐"
-	case StatusDone:
-		return "●"
-	case StatusPaused:
-		return "⏸"
-	case StatusCancelled:
-		return "✗"
-	default:
-		return "○"
+// addChecklistItems adds rows for task's checklist when it is expanded.
+func (m *model) addChecklistItems(task *TaskWithPath, topic, indent string) {
+	if !m.expandedTasks[taskKey(task)] {
+		return
+	}
+	for i, check := range parseChecklist(task.Task.Body) {
+		mark := "[ ]"
+		if check.done {
+			mark = "[x]"
+		}
+		m.items = append(m.items, item{
+			text:       indent + strings.Repeat(" ", check.depth) + mark + " " + check.text,
+			topic:      topic,
+			task:       task,
+			isCheck:    true,
+			checkIndex: i,
+		})
 	}
 }
 
+// toggleChecklistItem flips a checklist row and saves its task.
+func (m *model) toggleChecklistItem(it item) tea.Cmd {
+	items := parseChecklist(it.task.Task.Body)
+	if it.checkIndex >= len(items) {
+		return m.loadTasks
+	}
+	if err := it.task.Task.setChecklistItem(it.checkIndex, !items[it.checkIndex].done); err != nil {
+		m.err = err
+		return nil
+	}
+	if err := m.storage().UpdateTask(it.task); errors.Is(err, errConflict) {
+		m.undoMsg = "Not saved: " + err.Error()
+		return m.loadTasks
+	} else if err != nil {
+		m.err = err
+		return nil
+	}
+	m.buildItems()
+	return m.loadTasks
+}
+
+// taskKey identifies a task across reloads.
+func taskKey(t *TaskWithPath) string {
+	if t.Task.ID != "" {
+		return t.Task.ID
+	}
+	return t.FilePath
+}
+
+// taskLabel renders a task's list entry: status icon, priority, title, due
+// date, and markers for repeating and blocked tasks.
+func taskLabel(task *Task, byID map[string]*TaskWithPath) string {
+	title := task.Title
+	if task.Priority != 3 {
+		title = fmt.Sprintf("[%d] %s", task.Priority, title)
+	}
+	icon := getStatusIcon(task.Status)
+	if d, ok := statusDef(task.Status); ok && d.Color != "" {
+		icon = lipgloss.NewStyle().Foreground(lipgloss.Color(d.Color)).Render(icon)
+	}
+	label := icon + " " + title
+	if progress := task.progress(); progress != "" {
+		label += " [" + progress + "]"
+	}
+	if task.Due != nil {
+		label += " (due " + formatDate(task.Due) + ")"
+	}
+	if task.Recur != "" {
+		label += " ↻"
+	}
+	if len(openDependencies(task, byID)) > 0 {
+		label += " [blocked]"
+	}
+	return label
+}
+
+// getStatusIcon returns the workflow icon for status.
+func getStatusIcon(status TaskStatus) string {
+	return status.icon()
+}
+
 func (m model) View() string {
 	if m.err != nil {
 		return fmt.Sprintf("Error: %v\n\nPress 'q' to quit", m.err)
@@ -823,8 +1131,41 @@ func (m model) View() string {
 }
 
 func (m model) viewList() string {
-	s := "TADA - Todo Manager\n"
-	s += mutedStyle.Render("j/k: move • space: expand • enter: edit • a: add • r: refresh • d: delete • q: quit") + "\n\n"
+	s := "TADA - Todo Manager"
+	if running := runningTimer(m.tasks); running != nil && !m.showArchived {
+		elapsed := time.Since(running.Task.runningEntry().Start)
+		s += "  " + focusStyle.Render(fmt.Sprintf("⏱ %s %s", running.Task.Title, formatDuration(elapsed)))
+	}
+	s += "\n"
+	if m.showArchived {
+		s = "TADA - Archived Tasks\n"
+		s += mutedStyle.Render("j/k: move • space: expand • i: details • /: search • R: restore • u/ctrl+r: undo/redo • A: back to tasks • q: quit") + "\n\n"
+	} else {
+		s += mutedStyle.Render("j/k: move • space: expand/check • enter: edit • a: add • A: archive • /: search • r: refresh • d: delete • u/ctrl+r: undo/redo • q: quit") + "\n\n"
+	}
+	if len(m.viewNames) > 0 {
+		tabs := []string{"All"}
+		tabs = append(tabs, m.viewNames...)
+		for i, name := range tabs {
+			if i == 0 && m.view == "" || name == m.view {
+				tabs[i] = focusStyle.Render(name)
+			} else {
+				tabs[i] = mutedStyle.Render(name)
+			}
+		}
+		s += "Views: " + strings.Join(tabs, mutedStyle.Render(" • ")) + mutedStyle.Render("  (tab/shift+tab)") + "\n"
+	}
+	if m.searchMode || m.searchQuery != "" {
+		bar := "/" + m.searchQuery
+		if m.searchMode {
+			bar += "█"
+		}
+		s += focusStyle.Render(bar)
+		if m.searchErr != "" {
+			s += "  " + overdueStyle.Render(m.searchErr)
+		}
+		s += "\n"
+	}
 
 	if m.confirmDelete && m.pendingDelete != nil {
 		msg := focusStyle.Render("Delete task '") + m.pendingDelete.Task.Title + focusStyle.Render("'? (y/n)")
@@ -833,6 +1174,9 @@ func (m model) viewList() string {
 	}
 
 	if len(m.items) == 0 {
+		if m.searchQuery != "" {
+			return s + mutedStyle.Render("No tasks match. Press esc to clear the search.")
+		}
 		return s + mutedStyle.Render("No tasks found. Press 'a' to add a task.")
 	}
 
@@ -875,6 +1219,10 @@ func (m model) viewList() string {
 			}
 			line = icon + " " + line
 			line = topicStyle.Render(line)
+		} else if item.isCheck {
+			line = mutedStyle.Render(line)
+		} else if item.task != nil && item.task.Task.isOverdue(time.Now()) {
+			line = overdueStyle.Render(line)
 		}
 
 		if i == m.selected {
@@ -891,14 +1239,27 @@ func (m model) viewList() string {
 		s += line + "\n"
 
 		// Insert the popup directly below the selected item
-		if m.showDetails && i == m.selected && item.task != nil {
+		if m.showDetails && i == m.selected && item.task != nil && !item.isCheck {
 			task := item.task.Task
 			detail := lipgloss.NewStyle().Bold(true).Foreground(accent).Render("Task Details") + "\n"
+			detail += focusStyle.Render("ID: ") + task.ID + "\n"
 			detail += focusStyle.Render("Title: ") + task.Title + "\n"
 			detail += focusStyle.Render("Description: ") + task.Description + "\n"
 			detail += focusStyle.Render("Priority: ") + fmt.Sprintf("%d", task.Priority) + "\n"
 			detail += focusStyle.Render("Status: ") + string(task.Status) + "\n"
 			detail += focusStyle.Render("Tags: ") + strings.Join(task.Tags, ", ") + "\n"
+			if task.Due != nil {
+				detail += focusStyle.Render("Due: ") + formatDate(task.Due) + "\n"
+			}
+			if task.Scheduled != nil {
+				detail += focusStyle.Render("Scheduled: ") + formatDate(task.Scheduled) + "\n"
+			}
+			if task.Recur != "" {
+				detail += focusStyle.Render("Repeats: ") + task.Recur + "\n"
+			}
+			if open := openDependencies(task, indexByID(m.tasks)); len(open) > 0 {
+				detail += focusStyle.Render("Blocked by: ") + dependencyTitles(open) + "\n"
+			}
 			detail += mutedStyle.Render("(Press esc/i to close)")
 
 			popupStyle := lipgloss.NewStyle().
@@ -921,27 +1282,49 @@ func (m model) viewList() string {
 	return s
 }
 
+// formRow is one field of the edit and add forms.
+type formRow struct {
+	index int
+	label string
+	value string
+	help  string
+}
+
+// customFieldRows renders the declared custom fields for the forms.
+func (m model) customFieldRows() []formRow {
+	var rows []formRow
+	for i, f := range customFields {
+		help := "(" + f.Type + ")"
+		if choices := f.choices(); choices != nil {
+			help = "(h/l: " + strings.Join(choices, ", ") + ")"
+		}
+		rows = append(rows, formRow{numFormFields + i, f.Name + ":", m.editForm.fields[f.Name], help})
+	}
+	return rows
+}
+
 func (m model) viewEdit() string {
 	s := "Edit Task\n"
 	s += mutedStyle.Render("tab: next field • enter: save/cancel • esc: back") + "\n\n"
+	if m.editWarning != "" {
+		s += overdueStyle.Render(m.editWarning) + "\n\n"
+	}
 
-	fields := []struct {
-		label string
-		value string
-		help  string
-	}{
-		{"Title:", m.editForm.title, ""},
-		{"Description:", m.editForm.desc, ""},
-		{"Priority:", m.editForm.priority, "(1-5, default 3)"},
-		{"Status:", string(m.editForm.status), "(h/l to change)"},
-		{"Tags:", m.editForm.tags, "(comma separated)"},
+	fields := []formRow{
+		{fieldTitle, "Title:", m.editForm.title, ""},
+		{fieldDesc, "Description:", m.editForm.desc, ""},
+		{fieldPriority, "Priority:", m.editForm.priority, "(1-5, default 3)"},
+		{fieldStatus, "Status:", string(m.editForm.status), "(h/l to change)"},
+		{fieldTags, "Tags:", m.editForm.tags, "(comma separated)"},
 	}
+	fields = append(fields, m.customFieldRows()...)
+	fields = append(fields, formRow{fieldBody, "Notes (Markdown, enter for newline):", "\n" + m.editForm.body, ""})
 
-	for i, field := range fields {
+	for _, field := range fields {
 		label := field.label
 		value := field.value
 
-		if i == m.editForm.field {
+		if field.index == m.editForm.field {
 			label = focusStyle.Render(label)
 			value += "█" // cursor
 		}
@@ -959,13 +1342,13 @@ func (m model) viewEdit() string {
 	save := "Save"
 	cancel := "Cancel"
 
-	if m.editForm.field == 5 {
+	if m.editForm.field == fieldSave {
 		save = focusStyle.Render("[" + save + "]")
 	} else {
 		save = "[" + save + "]"
 	}
 
-	if m.editForm.field == 6 {
+	if m.editForm.field == fieldCancel {
 		cancel = focusStyle.Render("[" + cancel + "]")
 	} else {
 		cancel = "[" + cancel + "]"
@@ -980,23 +1363,24 @@ func (m model) viewAdd() string {
 	s := "Add New Task\n"
 	s += mutedStyle.Render("tab: next field • enter: add/cancel • esc: back") + "\n\n"
 
-	fields := []struct {
-		label string
-		value string
-		help  string
-	}{
-		{"Title:", m.editForm.title, "(required)"},
-		{"Description:", m.editForm.desc, ""},
-		{"Priority:", m.editForm.priority, "(1-5, default 3)"},
-		{"Status:", string(m.editForm.status), "(h/l to change)"},
-		{"Tags:", m.editForm.tags, "(comma separated)"},
+	if m.editWarning != "" {
+		s += overdueStyle.Render(m.editWarning) + "\n\n"
+	}
+
+	fields := []formRow{
+		{fieldTitle, "Title:", m.editForm.title, "(required)"},
+		{fieldDesc, "Description:", m.editForm.desc, ""},
+		{fieldPriority, "Priority:", m.editForm.priority, "(1-5, default 3)"},
+		{fieldStatus, "Status:", string(m.editForm.status), "(h/l to change)"},
+		{fieldTags, "Tags:", m.editForm.tags, "(comma separated)"},
 	}
+	fields = append(fields, m.customFieldRows()...)
 
-	for i, field := range fields {
+	for _, field := range fields {
 		label := field.label
 		value := field.value
 
-		if i == m.editForm.field {
+		if field.index == m.editForm.field {
 			label = focusStyle.Render(label)
 			value += "█" // cursor
 		}
@@ -1014,13 +1398,13 @@ func (m model) viewAdd() string {
 	add := "Add"
 	cancel := "Cancel"
 
-	if m.editForm.field == 5 {
+	if m.editForm.field == fieldSave {
 		add = focusStyle.Render("[" + add + "]")
 	} else {
 		add = "[" + add + "]"
 	}
 
-	if m.editForm.field == 6 {
+	if m.editForm.field == fieldCancel {
 		cancel = focusStyle.Render("[" + cancel + "]")
 	} else {
 		cancel = "[" + cancel + "]"
@@ -1032,8 +1416,10 @@ func (m model) viewAdd() string {
 }
 
 // RunTUI launches the Tada TUI application.
-func RunTUI() {
-	p := tea.NewProgram(initialModel())
+func RunTUI(store Storage) {
+	m := initialModel()
+	m.store = store
+	p := tea.NewProgram(m)
 	if _, err := p.Run(); err != nil {
 		styledErr := lipgloss.NewStyle().Foreground(cliError).Render(fmt.Sprintf("Error: %v", err))
 		fmt.Fprintln(os.Stderr, styledErr)
@@ -1041,8 +1427,9 @@ func RunTUI() {
 }
 
 // RunTUIWithConfig launches the Tada TUI with a custom config.
-func RunTUIWithConfig(cfg *Config) {
+func RunTUIWithConfig(store Storage, cfg *Config) {
 	m := initialModel()
+	m.store = store
 	m.applyConfig(cfg)
 	p := tea.NewProgram(m)
 	if _, err := p.Run(); err != nil {
@@ -1065,6 +1452,8 @@ func (m *model) applyConfig(cfg *Config) {
 		muted = lipgloss.Color("7")
 		warning = lipgloss.Color("11")
 	}
+	m.views = cfg.Views
+	m.viewNames = cfg.viewNames()
 	// Add more config-driven settings as needed
 }
 
@@ -3,6 +3,7 @@ package main
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -18,8 +19,10 @@ func TestAddTask_EmptyTitle_Error(t *testing.T) {
 }
 
 func TestSaveTask_FileWriteError(t *testing.T) {
-	// Use a file path that cannot be written to
-	m := model{editTask: &TaskWithPath{Task: &Task{Title: "Test"}, FilePath: "/root/forbidden.md"}, editForm: form{title: "Test"}}
+	// Use a file path that cannot be written to, in a directory that does
+	// not exist
+	dir := t.TempDir()
+	m := model{store: NewFileStore(dir), editTask: &TaskWithPath{Task: &Task{Title: "Test"}, FilePath: filepath.Join(dir, "missing", "forbidden.md")}, editForm: form{title: "Test"}}
 	m2, _ := m.saveTask()
 	if m2.(model).err == nil || !strings.Contains(m2.(model).err.Error(), "failed to save") {
 		t.Errorf("Expected file write error, got: %v", m2.(model).err)
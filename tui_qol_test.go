@@ -17,8 +17,11 @@ func makeTaskWithPath(title, topic string, status TaskStatus) *TaskWithPath {
 }
 
 func TestYankAndPaste(t *testing.T) {
-	m := model{}
-	task := makeTaskWithPath("TestTask", "", StatusTodo)
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{Title: "TestTask", Status: StatusTodo, Tags: []string{"x"}})
+	tasks, _ := store.LoadAllTasks()
+	task := tasks["work"][0]
+	m := model{store: store}
 	m.items = []item{{task: task}}
 	m.selected = 0
 	m2, _ := m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
@@ -26,19 +29,24 @@ func TestYankAndPaste(t *testing.T) {
 	if m2m.yankedTask == nil || m2m.yankedTask.Task.Title != "TestTask" {
 		t.Errorf("Expected yanked task to be set")
 	}
-	m2m.yankedTask = task
-	_, _ = m2m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
-	// Clean up any copied files (match slugified/lowercase names)
-	tasksDir := ".tada/tasks"
-	entries, _ := os.ReadDir(tasksDir)
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		if strings.Contains(strings.ToLower(entry.Name()), "testtask-copy") {
-			_ = os.Remove(tasksDir + "/" + entry.Name())
+	m2, _ = m2m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	loaded, _ := store.LoadAllTasks()
+	if len(loaded["work"]) != 2 || loaded["work"][0].Task.ID == loaded["work"][1].Task.ID {
+		t.Fatalf("Expected the pasted copy with its own ID, got %v", loaded["work"])
+	}
+	for _, pasted := range loaded["work"] {
+		if pasted.Task.Title != "TestTask" || len(pasted.Task.Tags) != 1 {
+			t.Errorf("Expected the copy to keep the task's fields, got %+v", pasted.Task)
 		}
 	}
+
+	// A paste that fails is reported
+	m2m = m2.(model)
+	m2m.yankedTask = makeTaskWithPath("Gone", "", StatusTodo)
+	m2, _ = m2m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if msg := m2.(model).undoMsg; !strings.HasPrefix(msg, "Not pasted:") {
+		t.Errorf("Expected the failed paste to be reported, got %q", msg)
+	}
 }
 
 func TestStatusCycle(t *testing.T) {
@@ -64,7 +72,7 @@ func TestStatusCycle(t *testing.T) {
 		t.Fatalf("Task not found after saving")
 	}
 	defer os.Remove(realTask.FilePath)
-	m := model{tasks: map[string][]*TaskWithPath{"": {realTask}}, items: []item{{task: realTask}}, selected: 0}
+	m := model{store: store, tasks: map[string][]*TaskWithPath{"": {realTask}}, items: []item{{task: realTask}}, selected: 0}
 	m.cycleTaskStatus(realTask, 1) // cycle forward
 	loaded, _ = store.LoadAllTasks()
 	var foundStatus TaskStatus
@@ -97,7 +105,7 @@ func TestDeleteTask(t *testing.T) {
 	f, _ := os.CreateTemp("", "testfile-*.md")
 	defer os.Remove(f.Name())
 	task := &TaskWithPath{Task: &Task{Title: "DelTask"}, FilePath: f.Name()}
-	m := model{items: []item{{task: task}}, selected: 0}
+	m := model{items: []item{{task: task}}, selected: 0, store: NewFileStore(t.TempDir())}
 	m2, _ := m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
 	_, _ = m2.(model).updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
 	if _, err := os.Stat(f.Name()); !os.IsNotExist(err) {
@@ -109,7 +117,7 @@ func TestUndoDeleteTask(t *testing.T) {
 	f, _ := os.CreateTemp("", "testfile-*.md")
 	defer os.Remove(f.Name())
 	task := &TaskWithPath{Task: &Task{Title: "UndoDelTask"}, FilePath: f.Name()}
-	m := model{items: []item{{task: task}}, selected: 0}
+	m := model{items: []item{{task: task}}, selected: 0, store: NewFileStore(t.TempDir())}
 	m2, _ := m.updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
 	m3, _ := m2.(model).updateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
 	if _, err := os.Stat(f.Name()); !os.IsNotExist(err) {
@@ -192,7 +200,7 @@ func TestCompletedTaskVisibleUntilExit(t *testing.T) {
 		t.Fatalf("Task not found after saving")
 	}
 	defer os.Remove(realTask.FilePath)
-	m := model{tasks: map[string][]*TaskWithPath{"": {realTask}}, selected: 0}
+	m := model{store: store, tasks: map[string][]*TaskWithPath{"": {realTask}}, selected: 0}
 	m.buildItems()
 	if len(m.items) == 0 || m.items[0].task.Task.Title != "CompleteMe" {
 		t.Fatalf("Task should be visible before completion")
@@ -230,7 +238,7 @@ func TestBulkSelectAndDelete(t *testing.T) {
 	t1 := &TaskWithPath{Task: &Task{Title: "Bulk1"}, FilePath: f1.Name()}
 	t2 := &TaskWithPath{Task: &Task{Title: "Bulk2"}, FilePath: f2.Name()}
 	tasks := map[string][]*TaskWithPath{"": {t1, t2}}
-	m := model{tasks: tasks, items: []item{{task: t1}, {task: t2}}, selected: 0}
+	m := model{tasks: tasks, items: []item{{task: t1}, {task: t2}}, selected: 0, store: NewFileStore(t.TempDir())}
 	m.buildItems()
 	m.selectedItems = map[int]struct{}{0: {}, 1: {}}
 
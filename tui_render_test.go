@@ -98,3 +98,87 @@ func TestUpdateAddViewTabAndEnter(t *testing.T) {
 		t.Errorf("Expected tab to move to next field")
 	}
 }
+
+func TestEditViewNotesField(t *testing.T) {
+	tempDir := t.TempDir()
+	store := NewFileStore(tempDir)
+	task := &Task{Title: "Notes", Status: StatusTodo}
+	if err := store.SaveTask("", task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	loaded, _ := store.GetTask(task.ID)
+
+	m := model{store: store, mode: editView, editTask: loaded}
+	m.initForm()
+	m.editForm.field = fieldBody
+	m2, _ := m.updateEditView(keyMsg("x"))
+	m = m2.(model)
+	m2, _ = m.updateEditView(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(model)
+	if !strings.HasSuffix(m.editForm.body, "x\n") || !strings.Contains(m.viewEdit(), "Notes") {
+		t.Fatalf("Expected notes field to accept text and newlines, got %q", m.editForm.body)
+	}
+	m.editForm.field = fieldSave
+	m.saveTask()
+
+	reloaded, _ := store.GetTask(task.ID)
+	if !strings.HasSuffix(reloaded.Task.Body, "x\n") {
+		t.Errorf("Expected edited body to be saved, got %q", reloaded.Task.Body)
+	}
+}
+
+func TestAddViewSkipsNotesField(t *testing.T) {
+	m := model{mode: addView}
+	m.editForm.field = fieldTags
+	m.nextField(1)
+	if m.editForm.field != fieldSave {
+		t.Errorf("Expected add view to skip the notes field, got field %d", m.editForm.field)
+	}
+}
+
+func TestEditFormClosesTask(t *testing.T) {
+	useWorkflow(t, teamWorkflow)
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("", &Task{Title: "Closing", Status: "review"})
+	store.SaveTask("", &Task{Title: "Stuck", Status: "todo"})
+	tasks, _ := store.LoadAllTasks()
+	m := model{tasks: tasks, store: store, expanded: map[string]bool{"": true}}
+	byTitle := map[string]*TaskWithPath{}
+	for _, task := range tasks[""] {
+		byTitle[task.Task.Title] = task
+	}
+
+	// A rejected status leaves the other edits unsaved and the task as it was
+	m.editTask = byTitle["Stuck"]
+	m.initForm()
+	m.editForm.title, m.editForm.status = "Renamed", StatusDone
+	next, _ := m.saveTask()
+	if next.(model).undoMsg == "" || byTitle["Stuck"].Task.Title != "Stuck" {
+		t.Errorf("Expected the rejected edit to leave the task alone, got %q", byTitle["Stuck"].Task.Title)
+	}
+
+	// Closing a task in the form queues it for archiving, like the s key
+	m.editTask = byTitle["Closing"]
+	m.initForm()
+	m.editForm.status = StatusDone
+	next, _ = m.saveTask()
+	m = next.(model)
+	if len(m.toArchive) != 1 || m.toArchive[0] != byTitle["Closing"] {
+		t.Fatalf("Expected the closed task to be queued for archiving, got %v", m.toArchive)
+	}
+
+	// So is a task added as closed
+	m.initAddFormWithTopic("")
+	m.editForm.title, m.editForm.status = "Already done", StatusDone
+	next, _ = m.addTask()
+	m = next.(model)
+	if len(m.toArchive) != 2 || m.toArchive[1].Task.Title != "Already done" {
+		t.Fatalf("Expected the added task to be queued for archiving, got %v", m.toArchive)
+	}
+
+	m.updateListView(keyMsg("q"))
+	archived, _ := store.LoadArchivedTasks()
+	if n := len(archived[""]); n != 2 {
+		t.Errorf("Expected both closed tasks to be archived on exit, got %d", n)
+	}
+}
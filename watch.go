@@ -0,0 +1,129 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	fstore "io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchInterval is how often the TUI checks the task files for changes.
+const watchInterval = time.Second
+
+// watchTickMsg asks the TUI to check whether task files changed on disk.
+type watchTickMsg struct{}
+
+// fingerprinter is implemented by stores that can cheaply tell whether
+// their contents changed, which lets the TUI reload on external edits.
+type fingerprinter interface {
+	Fingerprint() (string, error)
+}
+
+// Fingerprint summarizes the name, size and modification time of every
+// task file under tasks/ and archive/. It changes whenever a task file is
+// added, removed or rewritten, by tada or any other program.
+func (fs *FileStore) Fingerprint() (string, error) {
+	h := sha256.New()
+	for _, dir := range []string{TasksDir, ArchiveDir} {
+		root := filepath.Join(fs.basePath, dir)
+		err := filepath.WalkDir(root, func(path string, d fstore.DirEntry, err error) error {
+			if err != nil {
+				if path == root && os.IsNotExist(err) {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(path, ".md") {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil // removed while walking; the next check sees it
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00%d\n", path, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// watchTasks schedules the next change check.
+func (m model) watchTasks() tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg { return watchTickMsg{} })
+}
+
+// checkForChanges reloads the tasks when the store's fingerprint moved.
+// The TUI's own writes trigger a reload too, which is harmless.
+func (m model) checkForChanges() (tea.Model, tea.Cmd) {
+	f, ok := m.storage().(fingerprinter)
+	if !ok {
+		return m, nil // nothing to watch; stop ticking
+	}
+	fingerprint, err := f.Fingerprint()
+	if err != nil || fingerprint == m.diskFingerprint {
+		return m, m.watchTasks()
+	}
+	m.diskFingerprint = fingerprint
+	return m, tea.Batch(m.loadTasks, m.watchTasks())
+}
+
+// itemKey identifies a list row across reloads.
+func itemKey(it item) string {
+	switch {
+	case it.isTopic:
+		return "topic:" + it.topic
+	case it.isCheck:
+		return fmt.Sprintf("check:%s:%d", taskKey(it.task), it.checkIndex)
+	case it.task != nil:
+		return "task:" + taskKey(it.task)
+	}
+	return ""
+}
+
+// restoreSelection moves the cursor back to the row identified by key,
+// keeping it in range when that row is gone.
+func (m *model) restoreSelection(key string) {
+	if key != "" {
+		for i, it := range m.items {
+			if itemKey(it) == key {
+				m.selected = i
+				return
+			}
+		}
+	}
+	if m.selected >= len(m.items) {
+		m.selected = len(m.items) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+}
+
+// checkEditConflict warns when the task open in the edit view was changed
+// or removed on disk since it was opened.
+func (m *model) checkEditConflict() {
+	if m.mode != editView || m.editTask == nil || m.editTask.Hash == "" {
+		return
+	}
+	for _, list := range m.tasks {
+		for _, t := range list {
+			if t.FilePath != m.editTask.FilePath {
+				continue
+			}
+			if t.Hash != m.editTask.Hash {
+				m.editWarning = "This task was changed on disk since you opened it. Saving will be refused; press esc to discard your edits and see the new version."
+			}
+			return
+		}
+	}
+	m.editWarning = "This task was moved or deleted on disk since you opened it."
+}
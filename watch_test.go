@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFileStore_Fingerprint(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	empty, err := store.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.SaveTask("work", &Task{Title: "Watched", Status: StatusTodo})
+	added, _ := store.Fingerprint()
+	if added == empty {
+		t.Error("Expected adding a task to change the fingerprint")
+	}
+	if again, _ := store.Fingerprint(); again != added {
+		t.Error("Expected the fingerprint to be stable without changes")
+	}
+
+	tasks, _ := store.LoadAllTasks()
+	task := tasks["work"][0]
+	task.Task.Priority = 1
+	later := time.Now().Add(time.Second)
+	store.UpdateTask(task)
+	os.Chtimes(task.FilePath, later, later)
+	if edited, _ := store.Fingerprint(); edited == added {
+		t.Error("Expected editing a task to change the fingerprint")
+	}
+}
+
+// deliver runs a load command and feeds its result back into the model.
+func deliver(t *testing.T, m model, cmd tea.Cmd) model {
+	t.Helper()
+	next, _ := m.Update(cmd())
+	return next.(model)
+}
+
+func TestTUILiveReload(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("", &Task{Title: "First", Status: StatusTodo, Priority: 3})
+	store.SaveTask("", &Task{Title: "Second", Status: StatusTodo, Priority: 3})
+
+	m := initialModel()
+	m.store = store
+	m = deliver(t, m, m.loadTasks)
+	next, _ := m.checkForChanges()
+	m = next.(model)
+	for i, it := range m.items {
+		if it.task.Task.Title == "Second" {
+			m.selected = i
+		}
+	}
+
+	// Another process adds a task; the next check picks it up
+	other := NewFileStore(store.basePath)
+	other.SaveTask("", &Task{Title: "From elsewhere", Status: StatusTodo, Priority: 3})
+	next, cmd := m.checkForChanges()
+	m = next.(model)
+	if cmd == nil {
+		t.Fatal("Expected a reload to be scheduled")
+	}
+	m = deliver(t, m, m.loadTasks)
+	if len(m.items) != 3 {
+		t.Fatalf("Expected the external task to appear, got %d items", len(m.items))
+	}
+	if m.items[m.selected].task.Task.Title != "Second" {
+		t.Errorf("Expected the selection to stay on Second, got %s", m.items[m.selected].task.Task.Title)
+	}
+
+	// A task open in the edit view is changed underneath
+	m.mode = editView
+	m.editTask = m.items[m.selected].task
+	m.initForm()
+	tasks, _ := other.LoadAllTasks()
+	for _, task := range tasks[""] {
+		if task.Task.Title == "Second" {
+			task.Task.Description = "changed elsewhere"
+			other.UpdateTask(task)
+		}
+	}
+	m = deliver(t, m, m.loadTasks)
+	if !strings.Contains(m.viewEdit(), "changed on disk") {
+		t.Errorf("Expected the edit view to warn about the external change, got:\n%s", m.viewEdit())
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StatusDef configures one task status of the workflow.
+type StatusDef struct {
+	Name  TaskStatus `yaml:"name"`
+	Icon  string     `yaml:"icon,omitempty"`
+	Color string     `yaml:"color,omitempty"`
+	// Closed statuses end a task's life: closed tasks are archived and no
+	// longer block the tasks that depend on them.
+	Closed bool `yaml:"closed,omitempty"`
+	// Next lists the statuses a task may move to from this one. Empty
+	// allows any.
+	Next []TaskStatus `yaml:"next,omitempty"`
+}
+
+// defaultStatuses is the workflow used unless the config defines one.
+var defaultStatuses = []StatusDef{
+	{Name: StatusTodo, Icon: "○"},
+	{Name: StatusInProgress, Icon: "◐"},
+	{Name: StatusDone, Icon: "●", Closed: true},
+	{Name: StatusPaused, Icon: "⏸"},
+	{Name: StatusCancelled, Icon: "✗", Closed: true},
+}
+
+// statuses is the active workflow, in order. The first status is given to
+// new tasks and the first closed one to completed tasks.
+var statuses = defaultStatuses
+
+var errInvalidTransition = errors.New("status change not allowed")
+
+// setWorkflow makes defs the active workflow, or restores the default when
+// defs is empty. An invalid workflow is rejected and the active one kept.
+func setWorkflow(defs []StatusDef) error {
+	if len(defs) == 0 {
+		statuses = defaultStatuses
+		return nil
+	}
+	seen := make(map[TaskStatus]bool)
+	open, closed := false, false
+	for _, d := range defs {
+		if d.Name == "" {
+			return fmt.Errorf("a status has no name")
+		}
+		if seen[d.Name] {
+			return fmt.Errorf("status %q is defined twice", d.Name)
+		}
+		seen[d.Name] = true
+		open = open || !d.Closed
+		closed = closed || d.Closed
+	}
+	if !open || !closed {
+		return fmt.Errorf("the workflow needs at least one open and one closed status")
+	}
+	for _, d := range defs {
+		for _, next := range d.Next {
+			if !seen[next] {
+				return fmt.Errorf("status %q allows moving to unknown status %q", d.Name, next)
+			}
+		}
+	}
+	statuses = defs
+	return nil
+}
+
+// statusDef returns the definition of s in the active workflow.
+func statusDef(s TaskStatus) (StatusDef, bool) {
+	for _, d := range statuses {
+		if d.Name == s {
+			return d, true
+		}
+	}
+	return StatusDef{}, false
+}
+
+// statusNames lists the statuses of the active workflow in order, for
+// help texts and messages.
+func statusNames() string {
+	names := make([]string, len(statuses))
+	for i, d := range statuses {
+		names[i] = string(d.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// initialStatus is the status new and restored tasks get.
+func initialStatus() TaskStatus {
+	return statuses[0].Name
+}
+
+// doneStatus is the status completed tasks get.
+func doneStatus() TaskStatus {
+	for _, d := range statuses {
+		if d.Closed {
+			return d.Name
+		}
+	}
+	return StatusDone
+}
+
+// timerStatus is the status starting a timer moves a task to: in-progress
+// when the workflow has it. ok is false otherwise, and a timer runs in any
+// open status.
+func timerStatus() (status TaskStatus, ok bool) {
+	_, ok = statusDef(StatusInProgress)
+	return StatusInProgress, ok
+}
+
+// keepsTimer reports whether a running timer goes on when a task moves to
+// s: it stops when the task is closed, or leaves the timer status.
+func (s TaskStatus) keepsTimer() bool {
+	if s.isClosed() {
+		return false
+	}
+	running, ok := timerStatus()
+	return !ok || s == running
+}
+
+// rank is the position of s in the workflow; unknown statuses sort last.
+func (s TaskStatus) rank() int {
+	for i, d := range statuses {
+		if d.Name == s {
+			return i
+		}
+	}
+	return len(statuses)
+}
+
+// icon returns the symbol shown for s in the TUI.
+func (s TaskStatus) icon() string {
+	if d, ok := statusDef(s); ok && d.Icon != "" {
+		return d.Icon
+	}
+	return "○"
+}
+
+// style colors s with its configured color, falling back to fallback.
+func (s TaskStatus) style(fallback lipgloss.TerminalColor) lipgloss.Style {
+	if d, ok := statusDef(s); ok && d.Color != "" {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(d.Color))
+	}
+	return lipgloss.NewStyle().Foreground(fallback)
+}
+
+// checkTransition reports whether a task may move from one status to
+// another. Staying in a status is always allowed.
+func checkTransition(from, to TaskStatus) error {
+	if from == to {
+		return nil
+	}
+	d, ok := statusDef(from)
+	if !ok || len(d.Next) == 0 {
+		return nil
+	}
+	for _, next := range d.Next {
+		if next == to {
+			return nil
+		}
+	}
+	allowed := make([]string, len(d.Next))
+	for i, next := range d.Next {
+		allowed[i] = string(next)
+	}
+	return fmt.Errorf("%w: %s → %s (allowed from %s: %s)", errInvalidTransition, from, to, from, strings.Join(allowed, ", "))
+}
+
+// nextStatus steps from current through the workflow in direction (1 or
+// -1), skipping statuses a task in status from may not move to. It returns
+// from when nothing else is allowed.
+func nextStatus(current, from TaskStatus, direction int) TaskStatus {
+	n := len(statuses)
+	i := -1
+	if direction < 0 {
+		i = n
+	}
+	for k, d := range statuses {
+		if d.Name == current {
+			i = k
+		}
+	}
+	for step := 1; step <= n; step++ {
+		candidate := statuses[((i+step*direction)%n+n)%n].Name
+		if candidate == from || checkTransition(from, candidate) == nil {
+			return candidate
+		}
+	}
+	return from
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const teamWorkflow = `
+statuses:
+  - name: todo
+    next: [in-progress]
+  - name: in-progress
+    next: [review, blocked]
+  - name: review
+    icon: "R"
+    next: [done, in-progress]
+  - name: blocked
+    icon: "B"
+    next: [in-progress]
+  - name: done
+    closed: true
+  - name: wontfix
+    closed: true
+`
+
+func useWorkflow(t *testing.T, config string) {
+	t.Helper()
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(config), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := setWorkflow(cfg.Statuses); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { setWorkflow(nil) })
+}
+
+func TestSetWorkflowValidates(t *testing.T) {
+	t.Cleanup(func() { setWorkflow(nil) })
+	for _, defs := range [][]StatusDef{
+		{{Name: "todo"}, {Name: "todo", Closed: true}},
+		{{Name: "todo"}, {Name: "doing"}},
+		{{Name: "todo", Next: []TaskStatus{"shipped"}}, {Name: "done", Closed: true}},
+	} {
+		if err := setWorkflow(defs); err == nil {
+			t.Errorf("Expected %+v to be rejected", defs)
+		}
+	}
+	if statusNames() != "todo, in-progress, done, paused, cancelled" {
+		t.Errorf("Expected a rejected workflow to keep the default, got %s", statusNames())
+	}
+}
+
+func TestCustomWorkflow(t *testing.T) {
+	useWorkflow(t, teamWorkflow)
+	store := NewFileStore(t.TempDir())
+	runWithStore(NewAddCmd(store), "work/Ship feature")
+	runWithStore(NewAddCmd(store), "work/Fix typo")
+
+	if out := runWithStore(NewCompleteCmd(store), "work/Ship feature"); !strings.Contains(out, "status change not allowed: todo → done") {
+		t.Errorf("Expected completing from todo to be rejected, got: %s", out)
+	}
+	if out := runWithStore(NewEditCmd(store), "work/Ship feature", "--status", "review"); !strings.Contains(out, "allowed from todo: in-progress") {
+		t.Errorf("Expected todo → review to be rejected, got: %s", out)
+	}
+	runWithStore(NewEditCmd(store), "work/Ship feature", "--status", "in-progress")
+	runWithStore(NewEditCmd(store), "work/Ship feature", "--status", "review")
+
+	// The TUI skips statuses review may not move to
+	tasks, _ := store.LoadAllTasks()
+	var feature *TaskWithPath
+	for _, task := range tasks["work"] {
+		if task.Task.Title == "Ship feature" {
+			feature = task
+		}
+	}
+	m := model{tasks: tasks, store: store, expanded: map[string]bool{"work": true}}
+	m.buildItems()
+	if !strings.Contains(m.viewList(), "R Ship feature") {
+		t.Errorf("Expected the configured icon, got:\n%s", m.viewList())
+	}
+	m.cycleTaskStatus(feature, -1)
+	if feature.Task.Status != StatusInProgress {
+		t.Errorf("Expected review to cycle back to in-progress, got %s", feature.Task.Status)
+	}
+	m.cycleTaskStatus(feature, 1)
+	if feature.Task.Status != "review" {
+		t.Errorf("Expected in-progress to cycle to review, got %s", feature.Task.Status)
+	}
+
+	if out := runWithStore(NewCompleteCmd(store), "work/Ship feature"); !strings.Contains(out, "Task completed") {
+		t.Fatalf("Expected completing from review to work, got: %s", out)
+	}
+	if out := runWithStore(NewStatsCmd(store)); !strings.Contains(out, "todo: 1\n  in-progress: 0\n  review: 0\n  blocked: 0\n  done: 0\n  wontfix: 0") {
+		t.Errorf("Expected stats in workflow order, got: %s", out)
+	}
+
+	// Any closed status counts as finished
+	tasks, _ = store.LoadAllTasks()
+	typo := tasks["work"][0]
+	typo.Task.Status = "wontfix"
+	if _, err := completeTask(store, typo); err != nil {
+		t.Fatal(err)
+	}
+	archived, _ := store.LoadArchivedTasks()
+	closed := map[TaskStatus]bool{}
+	for _, task := range archived["work"] {
+		closed[task.Task.Status] = true
+	}
+	if !closed[StatusDone] || !closed["wontfix"] {
+		t.Errorf("Expected done and wontfix tasks in the archive, got %v", closed)
+	}
+}
+
+func TestClosingStatusArchives(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	store.SaveTask("work", &Task{ID: "aaaa1111", Title: "Water plants", Status: StatusTodo, Recur: "weekly"})
+
+	if out := runWithStore(NewEditCmd(store), "aaaa1111", "--status", "done", "--priority", "2"); !strings.Contains(out, "Task updated and archived") || !strings.Contains(out, "Next occurrence due") {
+		t.Fatalf("Expected edit --status done to archive the task, got: %s", out)
+	}
+	if out := runWithStore(NewAddCmd(store), "work/Old chore", "--status", "done"); !strings.Contains(out, "Task added and archived: Old chore") {
+		t.Fatalf("Expected add --status done to archive the task, got: %s", out)
+	}
+
+	tasks, _ := store.LoadAllTasks()
+	if len(tasks["work"]) != 1 || tasks["work"][0].Task.Status != StatusTodo {
+		t.Errorf("Expected only the next occurrence to stay open, got %v", tasks["work"])
+	}
+	archived, _ := store.LoadArchivedTasks()
+	byTitle := map[string]*Task{}
+	for _, task := range archived["work"] {
+		byTitle[task.Task.Title] = task.Task
+	}
+	if byTitle["Water plants"] == nil || byTitle["Water plants"].Priority != 2 || byTitle["Old chore"] == nil {
+		t.Errorf("Expected both tasks archived with their edits, got %v", archived["work"])
+	}
+
+	// Adding a closed task is one undo
+	if _, err := store.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	tasks, _ = store.LoadAllTasks()
+	archived, _ = store.LoadArchivedTasks()
+	if len(tasks["work"]) != 1 || len(archived["work"]) != 1 {
+		t.Errorf("Expected undo to remove the added task entirely, got %v and %v", tasks["work"], archived["work"])
+	}
+}
+
+func TestWorkflowDrivesReadyAndTimer(t *testing.T) {
+	useWorkflow(t, `
+statuses:
+  - name: backlog
+  - name: doing
+  - name: shipped
+    closed: true
+`)
+	now := time.Now()
+	task := &Task{Title: "Custom", Status: "backlog"}
+	if err := task.startTimer(now); err != nil || task.Status != "backlog" {
+		t.Fatalf("Expected the timer to start without a status change, got %s, %v", task.Status, err)
+	}
+	task.setStatus("doing")
+	if !isReady(task, nil) {
+		t.Error("Expected an open custom status to be ready")
+	}
+	if task.runningEntry() == nil {
+		t.Error("Expected an open status to keep the timer running")
+	}
+	task.setStatus("shipped")
+	if task.runningEntry() != nil || isReady(task, nil) {
+		t.Error("Expected closing the task to stop the timer and make it not ready")
+	}
+}